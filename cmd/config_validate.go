@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/azbuky/rosetta-vite/configuration"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/spf13/cobra"
+)
+
+var (
+	configValidateCmd = &cobra.Command{
+		Use:   "config:validate",
+		Short: "Validate the configuration and print it",
+		Long: `Loads the configuration the same way the run command does,
+from the environment and from the configuration file in CONFIG_FILE,
+and prints the resolved configuration. Environment variables take
+precedence over the configuration file.
+
+When calling this command, you may provide 1 argument:
+[1] the location of the configuration file, overriding CONFIG_FILE`,
+		RunE: runConfigValidateCmd,
+		Args: cobra.MaximumNArgs(1),
+	}
+)
+
+func runConfigValidateCmd(cmd *cobra.Command, args []string) error {
+	configFile := os.Getenv(configuration.ConfigFileEnv)
+	if len(args) > 0 {
+		configFile = args[0]
+	}
+
+	cfg, err := configuration.LoadConfigurationFile(configFile)
+	if err != nil {
+		return fmt.Errorf("%w: invalid configuration", err)
+	}
+
+	fmt.Println(types.PrettyPrintStruct(cfg))
+
+	return nil
+}
@@ -31,6 +31,8 @@ func init() {
 	rootCmd.AddCommand(runCmd)
 	rootCmd.AddCommand(utilsBootstrapCmd)
 	rootCmd.AddCommand(utilsSignCmd)
+	rootCmd.AddCommand(utilsGviteConfigCmd)
+	rootCmd.AddCommand(configValidateCmd)
 }
 
 // handleSignals handles OS signals so we can ensure we close database
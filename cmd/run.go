@@ -6,34 +6,21 @@ import (
 	"fmt"
 	"log"
 	"net/http"
-	"time"
+	"os"
+	"path"
+	"strings"
 
 	"github.com/azbuky/rosetta-vite/configuration"
+	"github.com/azbuky/rosetta-vite/metrics"
 	"github.com/azbuky/rosetta-vite/services"
 	"github.com/azbuky/rosetta-vite/vite"
 
 	"github.com/coinbase/rosetta-sdk-go/asserter"
 	"github.com/coinbase/rosetta-sdk-go/server"
-	"github.com/coinbase/rosetta-sdk-go/types"
 	"github.com/spf13/cobra"
 	"golang.org/x/sync/errgroup"
 )
 
-const (
-	// readTimeout is the maximum duration for reading the entire
-	// request, including the body.
-	readTimeout = 5 * time.Second
-
-	// writeTimeout is the maximum duration before timing out
-	// writes of the response. It is reset whenever a new
-	// request's header is read.
-	writeTimeout = 120 * time.Second
-
-	// idleTimeout is the maximum amount of time to wait for the
-	// next request when keep-alives are enabled.
-	idleTimeout = 30 * time.Second
-)
-
 var (
 	runCmd = &cobra.Command{
 		Use:   "run",
@@ -53,8 +40,8 @@ func runRunCmd(cmd *cobra.Command, args []string) error {
 	asserter, err := asserter.NewServer(
 		vite.OperationTypes,
 		vite.HistoricalBalanceSupported,
-		[]*types.NetworkIdentifier{cfg.Network},
-		vite.CallMethods,
+		cfg.NetworkIdentifiers(),
+		cfg.CallMethods,
 		vite.IncludeMempoolCoins,
 	)
 	if err != nil {
@@ -68,32 +55,44 @@ func runRunCmd(cmd *cobra.Command, args []string) error {
 
 	g, ctx := errgroup.WithContext(ctx)
 
-	var client *vite.Client
+	clients := services.Clients{}
+	indexers := services.Indexers{}
+	trackers := services.Trackers{}
 	if cfg.Mode == configuration.Online {
-		if !cfg.RemoteGvite {
-			g.Go(func() error {
-				return vite.StartGvite(ctx, cfg.GviteArguments, g)
-			})
-		}
-
-		var err error
-		client, err = vite.NewClient(cfg.GviteURL, cfg.InlineTransactions)
-		if err != nil {
-			return fmt.Errorf("%w: cannot initialize vite client", err)
+		for _, network := range cfg.Networks {
+			client, tracker, indexer, err := startNetwork(ctx, g, cfg, network)
+			if err != nil {
+				return err
+			}
+			defer client.Close()
+
+			name := network.Network.Network
+			clients[name] = client
+			if tracker != nil {
+				defer tracker.Close()
+				trackers[name] = tracker
+			}
+			if indexer != nil {
+				indexers[name] = indexer
+			}
 		}
-		defer client.Close()
 	}
 
-	router := services.NewBlockchainRouter(cfg, client, asserter)
+	router := services.NewBlockchainRouter(cfg, clients, indexers, trackers, asserter)
 
 	loggedRouter := server.LoggerMiddleware(router)
 	corsRouter := server.CorsMiddleware(loggedRouter)
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.Handler())
+	mux.Handle("/", metrics.Middleware(corsRouter))
+
 	server := &http.Server{
 		Addr:         fmt.Sprintf(":%d", cfg.Port),
-		Handler:      corsRouter,
-		ReadTimeout:  readTimeout,
-		WriteTimeout: writeTimeout,
-		IdleTimeout:  idleTimeout,
+		Handler:      mux,
+		ReadTimeout:  cfg.ReadTimeout,
+		WriteTimeout: cfg.WriteTimeout,
+		IdleTimeout:  cfg.IdleTimeout,
 	}
 
 	g.Go(func() error {
@@ -117,3 +116,108 @@ func runRunCmd(cmd *cobra.Command, args []string) error {
 
 	return err
 }
+
+// startNetwork starts the gvite node of a network unless it is remote
+// and creates its client, and its tracker and indexer when enabled.
+// When several networks are served each one has its own index directory.
+func startNetwork(
+	ctx context.Context,
+	g *errgroup.Group,
+	cfg *configuration.Configuration,
+	network *configuration.NetworkConfiguration,
+) (*vite.Client, *vite.Tracker, *vite.Indexer, error) {
+	if network.Devnet != nil {
+		if err := prepareDevnet(network); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	var supervisor *vite.GviteSupervisor
+	if !network.RemoteGvite {
+		if err := writeGviteConfig(cfg, network, network.GviteConfigFile); err != nil {
+			return nil, nil, nil, err
+		}
+
+		supervisor = vite.NewGviteSupervisor(
+			network.Network.Network,
+			cfg.GviteBinary,
+			network.GviteArguments,
+		)
+		g.Go(func() error {
+			return supervisor.Run(ctx)
+		})
+	}
+
+	subscriptionURL := ""
+	if cfg.Subscriptions {
+		subscriptionURL = network.GviteWSURL
+	}
+
+	client, err := vite.NewClient(network.GviteURL, &vite.ClientOptions{
+		Network:            network.Network.Network,
+		Supervisor:         supervisor,
+		SubscriptionURL:    subscriptionURL,
+		InlineTransactions: cfg.InlineTransactions,
+		MempoolAddresses:   cfg.MempoolAddresses,
+		CallMethods:        cfg.CallMethods,
+		PoWSolver:          cfg.PoWSolver,
+		PoWThreads:         cfg.PoWThreads,
+		BlockConcurrency:   cfg.BlockConcurrency,
+		BlockBatchSize:     cfg.BlockBatchSize,
+		BlockCacheSize:     cfg.BlockCacheSize,
+		ConfirmationDepth:  cfg.ConfirmationDepth,
+	})
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("%w: cannot initialize %s client", err, network.Network.Network)
+	}
+
+	g.Go(func() error {
+		return client.StartTokenRegistry(ctx)
+	})
+
+	g.Go(func() error {
+		return client.StartSubscriber(ctx)
+	})
+
+	if !cfg.Indexer && !cfg.Events {
+		return client, nil, nil, nil
+	}
+
+	indexDirectory := path.Join(configuration.DataDirectory, configuration.IndexDirectory)
+	if len(cfg.Networks) > 1 {
+		indexDirectory = path.Join(indexDirectory, strings.ToLower(network.Network.Network))
+	}
+
+	tracker, err := vite.NewTracker(client, indexDirectory, cfg.Indexer)
+	if err != nil {
+		client.Close()
+		return nil, nil, nil, fmt.Errorf("%w: cannot initialize %s tracker", err, network.Network.Network)
+	}
+
+	var indexer *vite.Indexer
+	if cfg.Indexer {
+		indexer = vite.NewIndexer(client, tracker)
+	}
+
+	g.Go(func() error {
+		return tracker.Start(ctx)
+	})
+
+	return client, tracker, indexer, nil
+}
+
+// prepareDevnet writes the bootstrap balances
+// of the devnet genesis for rosetta-cli.
+func prepareDevnet(network *configuration.NetworkConfiguration) error {
+	devnet := network.Devnet
+	if err := os.MkdirAll(path.Dir(devnet.BootstrapFile), os.ModePerm); err != nil {
+		return fmt.Errorf("%w: cannot create devnet directory", err)
+	}
+
+	if err := vite.GenerateBootstrapFile(devnet.GenesisFile, devnet.BootstrapFile); err != nil {
+		return fmt.Errorf("%w: cannot generate devnet bootstrap balances", err)
+	}
+	log.Printf("devnet bootstrap balances written to %s", devnet.BootstrapFile)
+
+	return nil
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/azbuky/rosetta-vite/configuration"
+	"github.com/azbuky/rosetta-vite/vite"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	utilsGviteConfigCmd = &cobra.Command{
+		Use:   "utils:gvite-config",
+		Short: "Generate the gvite config of the local gvite node",
+		Long: `Renders the gvite config of the network served by the
+local gvite node from the gvite config template, using the
+configuration in the environment and in CONFIG_FILE. The run
+command performs the same step before starting gvite.
+
+When calling this command, you may provide 1 argument:
+[1] the location of where to write the gvite config, overriding
+the location used by the run command`,
+		RunE: runUtilsGviteConfigCmd,
+		Args: cobra.MaximumNArgs(1),
+	}
+)
+
+func runUtilsGviteConfigCmd(cmd *cobra.Command, args []string) error {
+	cfg, err := configuration.LoadConfiguration()
+	if err != nil {
+		return fmt.Errorf("%w: unable to load configuration", err)
+	}
+
+	for _, network := range cfg.Networks {
+		if network.RemoteGvite {
+			continue
+		}
+
+		outputFile := network.GviteConfigFile
+		if len(args) > 0 {
+			outputFile = args[0]
+		}
+
+		return writeGviteConfig(cfg, network, outputFile)
+	}
+
+	return errors.New("no network uses the local gvite node")
+}
+
+// writeGviteConfig writes the gvite config of the local gvite node of a
+// network to outputFile. A devnet with a base gvite config uses it
+// instead of the gvite config template, with the ports and modules of
+// the network.
+func writeGviteConfig(
+	cfg *configuration.Configuration,
+	network *configuration.NetworkConfiguration,
+	outputFile string,
+) error {
+	devnet := network.Devnet
+	if devnet != nil && len(devnet.BaseGviteConfig) > 0 {
+		if err := vite.GenerateDevnetGviteConfig(
+			devnet.BaseGviteConfig,
+			network.Gvite,
+			outputFile,
+		); err != nil {
+			return fmt.Errorf("%w: cannot generate devnet gvite config", err)
+		}
+	} else if err := vite.GenerateGviteConfig(
+		cfg.GviteConfigTemplate,
+		network.Gvite,
+		outputFile,
+	); err != nil {
+		return fmt.Errorf("%w: cannot generate %s gvite config", err, network.Network.Network)
+	}
+
+	log.Printf("%s gvite config written to %s", network.Network.Network, outputFile)
+
+	return nil
+}
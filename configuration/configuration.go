@@ -4,11 +4,15 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/azbuky/rosetta-vite/vite"
 
 	"github.com/coinbase/rosetta-sdk-go/types"
+	viteTypes "github.com/vitelabs/go-vite/common/types"
 )
 
 // Mode is the setting that determines if
@@ -30,6 +34,10 @@ const (
 	// Testnet is the Vite Testnet.
 	Testnet string = "TESTNET"
 
+	// Devnet is a private Vite network
+	// started from a custom genesis file.
+	Devnet string = "DEVNET"
+
 	// DataDirectory is the default location for all
 	// persistent data.
 	DataDirectory = "/data"
@@ -38,8 +46,9 @@ const (
 	// to determine mode.
 	ModeEnv = "MODE"
 
-	// NetworkEnv is the environment variable
-	// read to determine network.
+	// NetworkEnv is the environment variable read to
+	// determine network. A comma separated list of
+	// networks is served from a single process.
 	NetworkEnv = "NETWORK"
 
 	// PortEnv is the environment variable
@@ -52,38 +61,240 @@ const (
 	// running gvite node.
 	GviteEnv = "GVITE"
 
+	// GviteNetworkEnvPrefix is the prefix of the optional
+	// environment variables used to connect a network to an
+	// already running gvite node, e.g. GVITE_TESTNET.
+	GviteNetworkEnvPrefix = "GVITE_"
+
 	// InlineTransactions is an optional environmen variable
 	// used to determine if transaction are returned inline
 	// in /block or as other_transactions
 	InlineTransactions = "INLINE_TXS"
 
-	// DefaultGviteURL is the default URL for
-	// a running gvite node. This is used
-	// when GviteEnv is not populated.
-	DefaultGviteURL = "http://localhost:48132/"
+	// MempoolAddressesEnv is an optional environment variable
+	// containing a comma separated list of addresses whose
+	// unreceived blocks are reported in /mempool
+	MempoolAddressesEnv = "MEMPOOL_ADDRESSES"
+
+	// IndexerEnv is an optional environment variable
+	// used to enable the transaction indexer backing
+	// /search/transactions
+	IndexerEnv = "INDEXER"
+
+	// EventsEnv is an optional environment variable
+	// used to enable block event tracking backing
+	// /events/blocks
+	EventsEnv = "EVENTS"
+
+	// SubscriptionsEnv is an optional environment variable
+	// used to subscribe to new blocks over a WebSocket
+	// connection to gvite instead of polling its status.
+	SubscriptionsEnv = "SUBSCRIPTIONS"
+
+	// GviteWSEnv is an optional environment variable
+	// containing the WebSocket URL of the remote gvite
+	// node, used for subscriptions.
+	GviteWSEnv = "GVITE_WS"
+
+	// GviteWSNetworkEnvPrefix is the prefix of the optional
+	// environment variables containing the WebSocket URL of
+	// the remote gvite node of a network, e.g. GVITE_WS_TESTNET.
+	GviteWSNetworkEnvPrefix = "GVITE_WS_"
+
+	// IndexDirectory is the location of the transaction
+	// index and block events inside DataDirectory.
+	IndexDirectory = "index"
+
+	// CallMethodsEnv is an optional environment variable
+	// containing a comma separated list of gvite methods
+	// allowed in /call. Defaults to all supported methods.
+	CallMethodsEnv = "CALL_METHODS"
+
+	// PoWSolverEnv is an optional environment variable
+	// used to select how PoW nonces are computed.
+	// Options: LOCAL or RPC. Defaults to LOCAL.
+	PoWSolverEnv = "POW_SOLVER"
+
+	// PoWThreadsEnv is an optional environment variable
+	// containing the number of goroutines used by the
+	// local PoW solver. Defaults to the number of CPUs.
+	PoWThreadsEnv = "POW_THREADS"
+
+	// BlockConcurrencyEnv is an optional environment variable
+	// containing the maximum number of account chains fetched
+	// concurrently when populating a block.
+	BlockConcurrencyEnv = "BLOCK_CONCURRENCY"
+
+	// BlockBatchSizeEnv is an optional environment variable
+	// containing the maximum number of account blocks
+	// requested in a single range query.
+	BlockBatchSizeEnv = "BLOCK_BATCH_SIZE"
+
+	// BlockCacheSizeEnv is an optional environment variable
+	// containing the maximum number of snapshot blocks
+	// cached, 0 disables the block cache.
+	BlockCacheSizeEnv = "BLOCK_CACHE_SIZE"
+
+	// ConfirmationDepthEnv is an optional environment variable
+	// containing the number of snapshot blocks after which
+	// a block is final and cached permanently.
+	ConfirmationDepthEnv = "CONFIRMATION_DEPTH"
+
+	// ReadTimeoutEnv is an optional environment variable
+	// containing the maximum duration for reading a request.
+	ReadTimeoutEnv = "READ_TIMEOUT"
+
+	// WriteTimeoutEnv is an optional environment variable
+	// containing the maximum duration for writing a response.
+	WriteTimeoutEnv = "WRITE_TIMEOUT"
+
+	// IdleTimeoutEnv is an optional environment variable
+	// containing the maximum duration to wait for the next
+	// request when keep-alives are enabled.
+	IdleTimeoutEnv = "IDLE_TIMEOUT"
+
+	// HealthMaxBlockAgeEnv is an optional environment variable
+	// containing the maximum age of the latest snapshot block
+	// of a ready node.
+	HealthMaxBlockAgeEnv = "HEALTH_MAX_BLOCK_AGE"
+
+	// HealthMaxSyncLagEnv is an optional environment variable
+	// containing the maximum number of snapshot blocks a ready
+	// node can be behind its peers while syncing.
+	HealthMaxSyncLagEnv = "HEALTH_MAX_SYNC_LAG"
+
+	// HealthTimeoutEnv is an optional environment variable
+	// containing the maximum duration of the readiness
+	// checks of a network.
+	HealthTimeoutEnv = "HEALTH_TIMEOUT"
+
+	// DefaultHealthTimeout is the default maximum
+	// duration of the readiness checks of a network.
+	DefaultHealthTimeout = 3 * time.Second
+
+	// DefaultReadTimeout is the default maximum duration
+	// for reading the entire request, including the body.
+	DefaultReadTimeout = 5 * time.Second
+
+	// DefaultWriteTimeout is the default maximum duration
+	// before timing out writes of the response.
+	DefaultWriteTimeout = 120 * time.Second
+
+	// DefaultIdleTimeout is the default maximum duration
+	// to wait for the next request when keep-alives are enabled.
+	DefaultIdleTimeout = 30 * time.Second
+
+	// DevnetGenesisEnv is the environment variable containing
+	// the path of the genesis file of the devnet. It is
+	// required when DEVNET is one of the networks.
+	DevnetGenesisEnv = "DEVNET_GENESIS"
+
+	// DevnetNetworkIDEnv is an optional environment variable
+	// containing the network id of the devnet.
+	DevnetNetworkIDEnv = "DEVNET_NETWORK_ID"
+
+	// DevnetGviteConfigEnv is an optional environment variable
+	// containing the path of a gvite config the devnet gvite
+	// config is generated from instead of the gvite config
+	// template.
+	DevnetGviteConfigEnv = "DEVNET_GVITE_CONFIG"
+
+	// DevnetDirectory is the location of the generated
+	// devnet files inside DataDirectory.
+	DevnetDirectory = "devnet"
 
 	// MiddlewareVersion is the version of rosetta-vite.
 	MiddlewareVersion = "0.2.0"
 )
 
+// NetworkConfiguration determines how a
+// network served by rosetta-vite is reached.
+type NetworkConfiguration struct {
+	Network        *types.NetworkIdentifier
+	GviteURL       string
+	GviteWSURL     string
+	RemoteGvite    bool
+	GviteArguments string
+
+	// Gvite contains the settings the config of the local
+	// gvite node is rendered with, nil if gvite is remote
+	Gvite           *vite.GviteConfig
+	GviteConfigFile string
+
+	// Devnet contains the settings of a DEVNET network
+	Devnet *DevnetConfiguration
+}
+
+// DevnetConfiguration determines how a devnet gvite
+// node and its bootstrap balances are generated.
+type DevnetConfiguration struct {
+	GenesisFile     string
+	NetworkID       int
+	BaseGviteConfig string
+	BootstrapFile   string
+}
+
 // Configuration determines how
 type Configuration struct {
-	Mode    Mode
-	Network *types.NetworkIdentifier
+	Mode     Mode
+	Networks []*NetworkConfiguration
 	//GenesisBlockIdentifier *types.BlockIdentifier
-	GviteURL           string
-	RemoteGvite        bool
 	Port               int
-	GviteArguments     string
 	InlineTransactions bool
+	MempoolAddresses   []viteTypes.Address
+	CallMethods        []string
+	Indexer            bool
+	Events             bool
+	Subscriptions      bool
+	PoWSolver          vite.PoWSolver
+	PoWThreads         int
+	BlockConcurrency   int
+	BlockBatchSize     uint64
+	BlockCacheSize     int
+	ConfirmationDepth  uint64
+	ReadTimeout        time.Duration
+	WriteTimeout       time.Duration
+	IdleTimeout        time.Duration
+	HealthMaxBlockAge  time.Duration
+	HealthMaxSyncLag   uint64
+	HealthTimeout      time.Duration
+
+	// GviteBinary and GviteConfigTemplate are
+	// used to start the local gvite node
+	GviteBinary         string
+	GviteConfigTemplate string
 }
 
-// LoadConfiguration attempts to create a new Configuration
-// using the ENVs in the environment.
+// NetworkIdentifiers returns the identifiers of all configured networks.
+func (c *Configuration) NetworkIdentifiers() []*types.NetworkIdentifier {
+	networks := make([]*types.NetworkIdentifier, len(c.Networks))
+	for i, network := range c.Networks {
+		networks[i] = network.Network
+	}
+
+	return networks
+}
+
+// LoadConfiguration attempts to create a new Configuration using
+// the ENVs in the environment and the configuration file in
+// CONFIG_FILE, if any.
 func LoadConfiguration() (*Configuration, error) {
+	return LoadConfigurationFile(os.Getenv(ConfigFileEnv))
+}
+
+// LoadConfigurationFile attempts to create a new Configuration using
+// the ENVs in the environment and a configuration file. The ENVs take
+// precedence over the configuration file, an empty path only uses
+// the ENVs.
+func LoadConfigurationFile(configFile string) (*Configuration, error) {
+	s, err := loadSettings(configFile)
+	if err != nil {
+		return nil, err
+	}
+
 	config := &Configuration{}
 
-	modeValue := Mode(os.Getenv(ModeEnv))
+	modeValue := Mode(s.get(ModeEnv))
 	switch modeValue {
 	case Online:
 		config.Mode = Online
@@ -95,54 +306,366 @@ func LoadConfiguration() (*Configuration, error) {
 		return nil, fmt.Errorf("%s is not a valid mode", modeValue)
 	}
 
-	networkValue := os.Getenv(NetworkEnv)
-	switch networkValue {
-	case Mainnet:
-		config.Network = &types.NetworkIdentifier{
-			Blockchain: vite.Blockchain,
-			Network:    vite.MainnetNetwork,
-		}
-		//config.GenesisBlockIdentifier = vite.MainnetGenesisBlockIdentifier
-		config.GviteArguments = vite.MainnetGviteArguments
-	case Testnet:
-		config.Network = &types.NetworkIdentifier{
-			Blockchain: vite.Blockchain,
-			Network:    vite.TestnetNetwork,
-		}
-		//config.GenesisBlockIdentifier = vite.TestnetGenesisBlockIdentifier
-		config.GviteArguments = vite.TestnetGviteArguments
-	case "":
+	networkValue := s.get(NetworkEnv)
+	if len(networkValue) == 0 {
 		return nil, errors.New("NETWORK must be populated")
-	default:
-		return nil, fmt.Errorf("%s is not a valid network", networkValue)
 	}
+	networks, err := loadNetworks(s, strings.Split(networkValue, ","))
+	if err != nil {
+		return nil, err
+	}
+	config.Networks = networks
 
-	config.GviteURL = DefaultGviteURL
-	envGviteURL := os.Getenv(GviteEnv)
-	if len(envGviteURL) > 0 {
-		config.RemoteGvite = true
-		config.GviteURL = envGviteURL
+	if err := loadGviteFiles(s, config); err != nil {
+		return nil, err
 	}
 
-	portValue := os.Getenv(PortEnv)
+	portValue := s.get(PortEnv)
 	if len(portValue) == 0 {
 		return nil, errors.New("PORT must be populated")
 	}
 
 	config.InlineTransactions = vite.InlineTransactions
-	inlineTransactions := os.Getenv(InlineTransactions)
+	inlineTransactions := s.get(InlineTransactions)
 	if len(inlineTransactions) > 0 {
 		inline, err := strconv.ParseBool(inlineTransactions)
-		if err == nil {
-			config.InlineTransactions = inline
+		if err != nil {
+			return nil, fmt.Errorf("%w: unable to parse %s %s", err, InlineTransactions, inlineTransactions)
+		}
+		config.InlineTransactions = inline
+	}
+
+	mempoolAddresses := s.get(MempoolAddressesEnv)
+	if len(mempoolAddresses) > 0 {
+		for _, addressValue := range strings.Split(mempoolAddresses, ",") {
+			address, err := viteTypes.HexToAddress(strings.TrimSpace(addressValue))
+			if err != nil {
+				return nil, fmt.Errorf("%w: invalid mempool address %s", err, addressValue)
+			}
+			config.MempoolAddresses = append(config.MempoolAddresses, address)
 		}
 	}
 
+	indexer := s.get(IndexerEnv)
+	if len(indexer) > 0 {
+		enabled, err := strconv.ParseBool(indexer)
+		if err != nil {
+			return nil, fmt.Errorf("%w: unable to parse %s %s", err, IndexerEnv, indexer)
+		}
+		config.Indexer = enabled
+	}
+
+	events := s.get(EventsEnv)
+	if len(events) > 0 {
+		enabled, err := strconv.ParseBool(events)
+		if err != nil {
+			return nil, fmt.Errorf("%w: unable to parse %s %s", err, EventsEnv, events)
+		}
+		config.Events = enabled
+	}
+
+	subscriptions := s.get(SubscriptionsEnv)
+	if len(subscriptions) > 0 {
+		enabled, err := strconv.ParseBool(subscriptions)
+		if err != nil {
+			return nil, fmt.Errorf("%w: unable to parse %s %s", err, SubscriptionsEnv, subscriptions)
+		}
+		config.Subscriptions = enabled
+	}
+	if config.Subscriptions {
+		for _, network := range config.Networks {
+			if network.Gvite != nil && !contains(network.Gvite.PublicModules, subscribeGviteModule) {
+				network.Gvite.PublicModules = append(
+					append([]string{}, network.Gvite.PublicModules...),
+					subscribeGviteModule,
+				)
+			}
+			if len(network.GviteWSURL) == 0 {
+				return nil, fmt.Errorf(
+					"%s requires %s%s for the remote gvite node of %s",
+					SubscriptionsEnv,
+					GviteWSNetworkEnvPrefix,
+					strings.ToUpper(network.Network.Network),
+					network.Network.Network,
+				)
+			}
+		}
+	}
+
+	config.CallMethods = vite.CallMethods
+	callMethods := s.get(CallMethodsEnv)
+	if len(callMethods) > 0 {
+		config.CallMethods = []string{}
+		for _, method := range strings.Split(callMethods, ",") {
+			config.CallMethods = append(config.CallMethods, strings.TrimSpace(method))
+		}
+		if err := vite.ValidateCallMethods(config.CallMethods); err != nil {
+			return nil, fmt.Errorf("%w: invalid %s", err, CallMethodsEnv)
+		}
+	}
+
+	config.PoWSolver = vite.LocalPoWSolver
+	powSolver := vite.PoWSolver(s.get(PoWSolverEnv))
+	switch powSolver {
+	case vite.LocalPoWSolver, vite.RpcPoWSolver:
+		config.PoWSolver = powSolver
+	case "":
+	default:
+		return nil, fmt.Errorf("%s is not a valid pow solver", powSolver)
+	}
+
+	powThreads := s.get(PoWThreadsEnv)
+	if len(powThreads) > 0 {
+		threads, err := strconv.Atoi(powThreads)
+		if err != nil {
+			return nil, fmt.Errorf("%w: unable to parse %s %s", err, PoWThreadsEnv, powThreads)
+		}
+		if threads <= 0 {
+			return nil, fmt.Errorf("%s must be positive", PoWThreadsEnv)
+		}
+		config.PoWThreads = threads
+	}
+
+	config.BlockConcurrency = vite.DefaultBlockConcurrency
+	blockConcurrency := s.get(BlockConcurrencyEnv)
+	if len(blockConcurrency) > 0 {
+		concurrency, err := strconv.Atoi(blockConcurrency)
+		if err != nil {
+			return nil, fmt.Errorf("%w: unable to parse %s %s", err, BlockConcurrencyEnv, blockConcurrency)
+		}
+		if concurrency <= 0 {
+			return nil, fmt.Errorf("%s must be positive", BlockConcurrencyEnv)
+		}
+		config.BlockConcurrency = concurrency
+	}
+
+	config.BlockBatchSize = vite.DefaultBlockBatchSize
+	blockBatchSize := s.get(BlockBatchSizeEnv)
+	if len(blockBatchSize) > 0 {
+		batchSize, err := strconv.ParseUint(blockBatchSize, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: unable to parse %s %s", err, BlockBatchSizeEnv, blockBatchSize)
+		}
+		if batchSize == 0 {
+			return nil, fmt.Errorf("%s must be positive", BlockBatchSizeEnv)
+		}
+		config.BlockBatchSize = batchSize
+	}
+
+	config.BlockCacheSize = vite.DefaultBlockCacheSize
+	blockCacheSize := s.get(BlockCacheSizeEnv)
+	if len(blockCacheSize) > 0 {
+		cacheSize, err := strconv.Atoi(blockCacheSize)
+		if err != nil {
+			return nil, fmt.Errorf("%w: unable to parse %s %s", err, BlockCacheSizeEnv, blockCacheSize)
+		}
+		if cacheSize < 0 {
+			return nil, fmt.Errorf("%s must not be negative", BlockCacheSizeEnv)
+		}
+		config.BlockCacheSize = cacheSize
+	}
+
+	config.ConfirmationDepth = vite.DefaultConfirmationDepth
+	confirmationDepth := s.get(ConfirmationDepthEnv)
+	if len(confirmationDepth) > 0 {
+		depth, err := strconv.ParseUint(confirmationDepth, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: unable to parse %s %s", err, ConfirmationDepthEnv, confirmationDepth)
+		}
+		config.ConfirmationDepth = depth
+	}
+
+	config.HealthMaxSyncLag = vite.DefaultHealthMaxSyncLag
+	healthMaxSyncLag := s.get(HealthMaxSyncLagEnv)
+	if len(healthMaxSyncLag) > 0 {
+		lag, err := strconv.ParseUint(healthMaxSyncLag, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: unable to parse %s %s", err, HealthMaxSyncLagEnv, healthMaxSyncLag)
+		}
+		config.HealthMaxSyncLag = lag
+	}
+
 	port, err := strconv.Atoi(portValue)
-	if err != nil || len(portValue) == 0 || port <= 0 {
+	if err != nil {
 		return nil, fmt.Errorf("%w: unable to parse port %s", err, portValue)
 	}
+	if port <= 0 || port > 65535 {
+		return nil, fmt.Errorf("%d is not a valid port", port)
+	}
 	config.Port = port
 
+	durations := []struct {
+		key     string
+		value   *time.Duration
+		initial time.Duration
+	}{
+		{ReadTimeoutEnv, &config.ReadTimeout, DefaultReadTimeout},
+		{WriteTimeoutEnv, &config.WriteTimeout, DefaultWriteTimeout},
+		{IdleTimeoutEnv, &config.IdleTimeout, DefaultIdleTimeout},
+		{HealthMaxBlockAgeEnv, &config.HealthMaxBlockAge, vite.DefaultHealthMaxBlockAge},
+		{HealthTimeoutEnv, &config.HealthTimeout, DefaultHealthTimeout},
+	}
+	for _, setting := range durations {
+		*setting.value = setting.initial
+		durationValue := s.get(setting.key)
+		if len(durationValue) == 0 {
+			continue
+		}
+		duration, err := time.ParseDuration(durationValue)
+		if err != nil {
+			return nil, fmt.Errorf("%w: unable to parse %s %s", err, setting.key, durationValue)
+		}
+		if duration <= 0 {
+			return nil, fmt.Errorf("%s must be positive", setting.key)
+		}
+		*setting.value = duration
+	}
+
 	return config, nil
 }
+
+// networkKey returns the name of a network as used in settings,
+// e.g. in GVITE_<NETWORK>. Network names are case insensitive.
+func networkKey(network string) string {
+	return strings.ToUpper(strings.TrimSpace(network))
+}
+
+// loadNetworks creates the configuration of every network. A network
+// uses the gvite node of GVITE_<NETWORK>, or of GVITE when a single
+// network is configured, and otherwise the local gvite node. Only one
+// network can use the local gvite node.
+func loadNetworks(s settings, networkValues []string) ([]*NetworkConfiguration, error) {
+	envGviteURL := s.get(GviteEnv)
+	if len(envGviteURL) > 0 && len(networkValues) > 1 {
+		return nil, fmt.Errorf("%s can only be used with a single network, use %s<NETWORK>", GviteEnv, GviteNetworkEnvPrefix)
+	}
+	envGviteWSURL := s.get(GviteWSEnv)
+	if len(envGviteWSURL) > 0 && len(networkValues) > 1 {
+		return nil, fmt.Errorf("%s can only be used with a single network, use %s<NETWORK>", GviteWSEnv, GviteWSNetworkEnvPrefix)
+	}
+
+	networks := []*NetworkConfiguration{}
+	seen := map[string]bool{}
+	localNetwork := ""
+	for _, networkValue := range networkValues {
+		networkValue = networkKey(networkValue)
+		if seen[networkValue] {
+			return nil, fmt.Errorf("network %s is configured twice", networkValue)
+		}
+		seen[networkValue] = true
+
+		network := &NetworkConfiguration{}
+		networkID := 0
+		switch networkValue {
+		case Mainnet:
+			network.Network = &types.NetworkIdentifier{
+				Blockchain: vite.Blockchain,
+				Network:    vite.MainnetNetwork,
+			}
+			networkID = vite.MainnetNetworkID
+		case Testnet:
+			network.Network = &types.NetworkIdentifier{
+				Blockchain: vite.Blockchain,
+				Network:    vite.TestnetNetwork,
+			}
+			networkID = vite.TestnetNetworkID
+		case Devnet:
+			network.Network = &types.NetworkIdentifier{
+				Blockchain: vite.Blockchain,
+				Network:    vite.DevnetNetwork,
+			}
+			devnet, err := loadDevnet(s)
+			if err != nil {
+				return nil, err
+			}
+			network.Devnet = devnet
+			networkID = devnet.NetworkID
+		case "":
+			return nil, errors.New("NETWORK must not contain empty networks")
+		default:
+			return nil, fmt.Errorf("%s is not a valid network", networkValue)
+		}
+
+		gviteURL := s.get(GviteNetworkEnvPrefix + networkValue)
+		if len(gviteURL) == 0 {
+			gviteURL = envGviteURL
+		}
+		if len(gviteURL) > 0 {
+			network.RemoteGvite = true
+			network.GviteURL = gviteURL
+			network.GviteWSURL = s.get(GviteWSNetworkEnvPrefix + networkValue)
+			if len(network.GviteWSURL) == 0 {
+				network.GviteWSURL = envGviteWSURL
+			}
+			networks = append(networks, network)
+			continue
+		}
+
+		if len(localNetwork) > 0 {
+			return nil, fmt.Errorf(
+				"%s and %s cannot both use the local gvite node, set %s%s",
+				localNetwork,
+				networkValue,
+				GviteNetworkEnvPrefix,
+				networkValue,
+			)
+		}
+		localNetwork = networkValue
+
+		gvite, err := loadGvite(s, networkID, network.Devnet)
+		if err != nil {
+			return nil, err
+		}
+		network.Gvite = gvite
+		network.GviteURL = fmt.Sprintf(localGviteURL, gvite.HTTPPort)
+		network.GviteWSURL = fmt.Sprintf(localGviteWSURL, gvite.WSPort)
+		network.GviteConfigFile = path.Join(DataDirectory, GviteDirectory, vite.GviteConfigFile)
+		if network.Devnet != nil {
+			network.GviteConfigFile = path.Join(DataDirectory, DevnetDirectory, vite.GviteConfigFile)
+		}
+		network.GviteArguments = vite.GviteArguments(network.GviteConfigFile)
+
+		networks = append(networks, network)
+	}
+
+	return networks, nil
+}
+
+// loadDevnet creates the configuration of the devnet.
+func loadDevnet(s settings) (*DevnetConfiguration, error) {
+	devnetDirectory := path.Join(DataDirectory, DevnetDirectory)
+	devnet := &DevnetConfiguration{
+		NetworkID:     vite.DefaultDevnetNetworkID,
+		BootstrapFile: path.Join(devnetDirectory, vite.DevnetBootstrapFile),
+	}
+
+	devnet.GenesisFile = s.get(DevnetGenesisEnv)
+	if len(devnet.GenesisFile) == 0 {
+		return nil, fmt.Errorf("%s must be populated for %s", DevnetGenesisEnv, Devnet)
+	}
+	if _, err := os.Stat(devnet.GenesisFile); err != nil {
+		return nil, fmt.Errorf("%w: unable to read %s %s", err, DevnetGenesisEnv, devnet.GenesisFile)
+	}
+
+	networkID := s.get(DevnetNetworkIDEnv)
+	if len(networkID) > 0 {
+		id, err := strconv.Atoi(networkID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: unable to parse %s %s", err, DevnetNetworkIDEnv, networkID)
+		}
+		if id <= 2 {
+			return nil, fmt.Errorf("%s must be greater than 2, 1 and 2 are the mainnet and testnet", DevnetNetworkIDEnv)
+		}
+		devnet.NetworkID = id
+	}
+
+	gviteConfig := s.get(DevnetGviteConfigEnv)
+	if len(gviteConfig) > 0 {
+		if _, err := os.Stat(gviteConfig); err != nil {
+			return nil, fmt.Errorf("%w: unable to read %s %s", err, DevnetGviteConfigEnv, gviteConfig)
+		}
+		devnet.BaseGviteConfig = gviteConfig
+	}
+
+	return devnet, nil
+}
@@ -0,0 +1,103 @@
+package configuration
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+)
+
+func TestLoadConfigurationNetworkCase(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		env      map[string]string
+		expected map[string]string
+	}{
+		{
+			name: "lower case network with GVITE_<NETWORK>",
+			file: "mode: ONLINE\nport: 8080\nnetworks: [mainnet]\n",
+			env: map[string]string{
+				GviteNetworkEnvPrefix + Mainnet: "http://mainnet:48132",
+			},
+			expected: map[string]string{
+				"mainnet": "http://mainnet:48132",
+			},
+		},
+		{
+			name: "mixed case networks and gvite_urls",
+			file: "mode: ONLINE\nport: 8080\nnetworks: [Mainnet, testnet]\n" +
+				"gvite_urls:\n  mainnet: http://mainnet:48132\n  TestNet: http://testnet:48132\n",
+			expected: map[string]string{
+				"mainnet": "http://mainnet:48132",
+				"testnet": "http://testnet:48132",
+			},
+		},
+		{
+			name: "mixed case NETWORK",
+			file: "mode: ONLINE\nport: 8080\n",
+			env: map[string]string{
+				NetworkEnv:                      "mAinNet",
+				GviteNetworkEnvPrefix + Mainnet: "http://mainnet:48132",
+			},
+			expected: map[string]string{
+				"mainnet": "http://mainnet:48132",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "configuration")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+
+			configFile := path.Join(dir, "config.yaml")
+			if err := ioutil.WriteFile(configFile, []byte(test.file), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			for key, value := range test.env {
+				os.Setenv(key, value)
+				defer os.Unsetenv(key)
+			}
+
+			config, err := LoadConfigurationFile(configFile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(config.Networks) != len(test.expected) {
+				t.Fatalf("expected %d networks, got %d", len(test.expected), len(config.Networks))
+			}
+			for _, network := range config.Networks {
+				gviteURL, ok := test.expected[network.Network.Network]
+				if !ok {
+					t.Fatalf("unexpected network %s", network.Network.Network)
+				}
+				if !network.RemoteGvite || network.GviteURL != gviteURL {
+					t.Fatalf("expected %s to use %s, got %s", network.Network.Network, gviteURL, network.GviteURL)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadConfigurationNetworkDuplicate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "configuration")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	configFile := path.Join(dir, "config.yaml")
+	content := "mode: ONLINE\nport: 8080\nnetworks: [mainnet, MAINNET]\ngvite_urls:\n  MAINNET: http://mainnet:48132\n"
+	if err := ioutil.WriteFile(configFile, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := LoadConfigurationFile(configFile); err == nil {
+		t.Fatal("expected an error for a network configured twice")
+	}
+}
@@ -0,0 +1,197 @@
+package configuration
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// ConfigFileEnv is an optional environment variable containing
+// the path of a YAML or JSON configuration file. Settings in
+// the environment take precedence over the configuration file.
+const ConfigFileEnv = "CONFIG_FILE"
+
+// fileConfiguration is the content of a configuration file,
+// every setting corresponds to an environment variable.
+type fileConfiguration struct {
+	Mode              string            `yaml:"mode"`
+	Networks          []string          `yaml:"networks"`
+	Gvite             string            `yaml:"gvite"`
+	GviteURLs         map[string]string `yaml:"gvite_urls"`
+	GviteWS           string            `yaml:"gvite_ws"`
+	GviteWSURLs       map[string]string `yaml:"gvite_ws_urls"`
+	Subscriptions     *bool             `yaml:"subscriptions"`
+	Port              *int              `yaml:"port"`
+	InlineTxs         *bool             `yaml:"inline_txs"`
+	MempoolAddresses  []string          `yaml:"mempool_addresses"`
+	CallMethods       []string          `yaml:"call_methods"`
+	Indexer           *bool             `yaml:"indexer"`
+	Events            *bool             `yaml:"events"`
+	PoWSolver         string            `yaml:"pow_solver"`
+	PoWThreads        *int              `yaml:"pow_threads"`
+	BlockConcurrency  *int              `yaml:"block_concurrency"`
+	BlockBatchSize    *uint64           `yaml:"block_batch_size"`
+	BlockCacheSize    *int              `yaml:"block_cache_size"`
+	ConfirmationDepth *uint64           `yaml:"confirmation_depth"`
+
+	Timeouts struct {
+		Read  string `yaml:"read"`
+		Write string `yaml:"write"`
+		Idle  string `yaml:"idle"`
+	} `yaml:"timeouts"`
+
+	Health struct {
+		MaxBlockAge string  `yaml:"max_block_age"`
+		MaxSyncLag  *uint64 `yaml:"max_sync_lag"`
+		Timeout     string  `yaml:"timeout"`
+	} `yaml:"health"`
+
+	GviteNode struct {
+		Binary         string   `yaml:"binary"`
+		ConfigTemplate string   `yaml:"config_template"`
+		DataDir        string   `yaml:"data_dir"`
+		RPCPort        *int     `yaml:"rpc_port"`
+		WSPort         *int     `yaml:"ws_port"`
+		Modules        []string `yaml:"modules"`
+		Bootnodes      []string `yaml:"bootnodes"`
+		LogLevel       string   `yaml:"log_level"`
+	} `yaml:"gvite_node"`
+
+	Devnet struct {
+		Genesis     string `yaml:"genesis"`
+		NetworkID   *int   `yaml:"network_id"`
+		GviteConfig string `yaml:"gvite_config"`
+	} `yaml:"devnet"`
+}
+
+// settings contains the settings of a configuration file
+// keyed by the name of the matching environment variable.
+type settings map[string]string
+
+// get returns the value of a setting, the environment
+// takes precedence over the configuration file.
+func (s settings) get(key string) string {
+	if value, ok := os.LookupEnv(key); ok && len(value) > 0 {
+		return value
+	}
+
+	return s[key]
+}
+
+// loadSettings reads a configuration file, an empty path returns no
+// settings. Unknown keys are rejected so that typos are not ignored.
+func loadSettings(configFile string) (settings, error) {
+	s := settings{}
+	if len(configFile) == 0 {
+		return s, nil
+	}
+
+	content, err := ioutil.ReadFile(path.Clean(configFile))
+	if err != nil {
+		return nil, fmt.Errorf("%w: unable to read configuration file %s", err, configFile)
+	}
+
+	file := &fileConfiguration{}
+	if err := yaml.UnmarshalStrict(content, file); err != nil {
+		return nil, fmt.Errorf("%w: unable to parse configuration file %s", err, configFile)
+	}
+
+	s.set(ModeEnv, file.Mode)
+	s.set(NetworkEnv, strings.Join(file.Networks, ","))
+	s.set(GviteEnv, file.Gvite)
+	if err := s.setNetworkURLs("gvite_urls", GviteNetworkEnvPrefix, file.GviteURLs, file.Networks); err != nil {
+		return nil, fmt.Errorf("%w: invalid configuration file %s", err, configFile)
+	}
+	s.set(GviteWSEnv, file.GviteWS)
+	if err := s.setNetworkURLs("gvite_ws_urls", GviteWSNetworkEnvPrefix, file.GviteWSURLs, file.Networks); err != nil {
+		return nil, fmt.Errorf("%w: invalid configuration file %s", err, configFile)
+	}
+	s.setBool(SubscriptionsEnv, file.Subscriptions)
+	s.setInt(PortEnv, file.Port)
+	s.setBool(InlineTransactions, file.InlineTxs)
+	s.set(MempoolAddressesEnv, strings.Join(file.MempoolAddresses, ","))
+	s.set(CallMethodsEnv, strings.Join(file.CallMethods, ","))
+	s.setBool(IndexerEnv, file.Indexer)
+	s.setBool(EventsEnv, file.Events)
+	s.set(PoWSolverEnv, file.PoWSolver)
+	s.setInt(PoWThreadsEnv, file.PoWThreads)
+	s.setInt(BlockConcurrencyEnv, file.BlockConcurrency)
+	s.setUint(BlockBatchSizeEnv, file.BlockBatchSize)
+	s.setInt(BlockCacheSizeEnv, file.BlockCacheSize)
+	s.setUint(ConfirmationDepthEnv, file.ConfirmationDepth)
+	s.set(ReadTimeoutEnv, file.Timeouts.Read)
+	s.set(WriteTimeoutEnv, file.Timeouts.Write)
+	s.set(IdleTimeoutEnv, file.Timeouts.Idle)
+	s.set(HealthMaxBlockAgeEnv, file.Health.MaxBlockAge)
+	s.setUint(HealthMaxSyncLagEnv, file.Health.MaxSyncLag)
+	s.set(HealthTimeoutEnv, file.Health.Timeout)
+	s.set(GviteBinaryEnv, file.GviteNode.Binary)
+	s.set(GviteConfigTemplateEnv, file.GviteNode.ConfigTemplate)
+	s.set(GviteDataDirEnv, file.GviteNode.DataDir)
+	s.setInt(GviteRPCPortEnv, file.GviteNode.RPCPort)
+	s.setInt(GviteWSPortEnv, file.GviteNode.WSPort)
+	s.set(GviteModulesEnv, strings.Join(file.GviteNode.Modules, ","))
+	s.set(GviteBootnodesEnv, strings.Join(file.GviteNode.Bootnodes, ","))
+	s.set(GviteLogLevelEnv, file.GviteNode.LogLevel)
+	s.set(DevnetGenesisEnv, file.Devnet.Genesis)
+	s.setInt(DevnetNetworkIDEnv, file.Devnet.NetworkID)
+	s.set(DevnetGviteConfigEnv, file.Devnet.GviteConfig)
+
+	return s, nil
+}
+
+// setNetworkURLs sets the gvite URLs keyed by network. Networks must be
+// valid and, if the configuration file lists its networks, one of them.
+func (s settings) setNetworkURLs(
+	key string,
+	prefix string,
+	urls map[string]string,
+	fileNetworks []string,
+) error {
+	configured := []string{}
+	for _, network := range fileNetworks {
+		configured = append(configured, networkKey(network))
+	}
+
+	for network, url := range urls {
+		name := networkKey(network)
+		if !contains([]string{Mainnet, Testnet, Devnet}, name) {
+			return fmt.Errorf("%s in %s is not a valid network", network, key)
+		}
+		if len(configured) > 0 && !contains(configured, name) {
+			return fmt.Errorf("%s in %s is not a configured network", network, key)
+		}
+		s.set(prefix+name, url)
+	}
+
+	return nil
+}
+
+func (s settings) set(key string, value string) {
+	if len(value) > 0 {
+		s[key] = value
+	}
+}
+
+func (s settings) setBool(key string, value *bool) {
+	if value != nil {
+		s[key] = strconv.FormatBool(*value)
+	}
+}
+
+func (s settings) setInt(key string, value *int) {
+	if value != nil {
+		s[key] = strconv.Itoa(*value)
+	}
+}
+
+func (s settings) setUint(key string, value *uint64) {
+	if value != nil {
+		s[key] = strconv.FormatUint(*value, 10)
+	}
+}
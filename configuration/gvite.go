@@ -0,0 +1,172 @@
+package configuration
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/azbuky/rosetta-vite/vite"
+)
+
+const (
+	// GviteBinaryEnv is an optional environment variable
+	// containing the location of the gvite binary.
+	GviteBinaryEnv = "GVITE_BINARY"
+
+	// GviteConfigTemplateEnv is an optional environment variable
+	// containing the path of a text/template the gvite config is
+	// rendered from. Defaults to vite.DefaultGviteConfigTemplate.
+	GviteConfigTemplateEnv = "GVITE_CONFIG_TEMPLATE"
+
+	// GviteDataDirEnv is an optional environment variable
+	// containing the data directory of the local gvite node.
+	GviteDataDirEnv = "GVITE_DATA_DIR"
+
+	// GviteRPCPortEnv is an optional environment variable
+	// containing the RPC port of the local gvite node.
+	GviteRPCPortEnv = "GVITE_RPC_PORT"
+
+	// GviteWSPortEnv is an optional environment variable
+	// containing the WebSocket port of the local gvite node.
+	GviteWSPortEnv = "GVITE_WS_PORT"
+
+	// GviteModulesEnv is an optional environment variable
+	// containing a comma separated list of the public RPC
+	// modules enabled on the local gvite node.
+	GviteModulesEnv = "GVITE_MODULES"
+
+	// GviteBootnodesEnv is an optional environment variable
+	// containing a comma separated list of the boot seeds of
+	// the local gvite node. Discovery is disabled without
+	// boot seeds.
+	GviteBootnodesEnv = "GVITE_BOOTNODES"
+
+	// GviteLogLevelEnv is an optional environment variable
+	// containing the log level of the local gvite node.
+	GviteLogLevelEnv = "GVITE_LOG_LEVEL"
+
+	// GviteDirectory is the location of the data and
+	// the config of the local gvite node inside DataDirectory.
+	GviteDirectory = "gvite"
+
+	// localGviteURL is the URL of the local gvite node.
+	localGviteURL = "http://localhost:%d/"
+
+	// localGviteWSURL is the WebSocket URL of the local gvite node.
+	localGviteWSURL = "ws://localhost:%d/"
+)
+
+// requiredGviteModules are the gvite RPC modules
+// rosetta-vite cannot run without.
+var requiredGviteModules = []string{"ledger", "net"}
+
+// subscribeGviteModule is the gvite RPC module
+// required by subscriptions.
+const subscribeGviteModule = "subscribe"
+
+// loadGvite creates the configuration of the local gvite node of a
+// network. A devnet is started from its genesis file without boot
+// seeds unless GVITE_BOOTNODES is set.
+func loadGvite(s settings, networkID int, devnet *DevnetConfiguration) (*vite.GviteConfig, error) {
+	config := &vite.GviteConfig{
+		DataDir:       path.Join(DataDirectory, GviteDirectory),
+		NetID:         networkID,
+		HTTPPort:      vite.DefaultGviteHTTPPort,
+		WSPort:        vite.DefaultGviteWSPort,
+		PublicModules: vite.DefaultGvitePublicModules,
+		BootSeeds:     vite.DefaultGviteBootSeeds,
+		LogLevel:      vite.DefaultGviteLogLevel,
+	}
+	if devnet != nil {
+		config.GenesisFile = devnet.GenesisFile
+		config.BootSeeds = []string{}
+	}
+
+	if dataDir := s.get(GviteDataDirEnv); len(dataDir) > 0 {
+		config.DataDir = dataDir
+	}
+
+	ports := []struct {
+		key   string
+		value *int
+	}{
+		{GviteRPCPortEnv, &config.HTTPPort},
+		{GviteWSPortEnv, &config.WSPort},
+	}
+	for _, port := range ports {
+		portValue := s.get(port.key)
+		if len(portValue) == 0 {
+			continue
+		}
+		parsed, err := strconv.Atoi(portValue)
+		if err != nil {
+			return nil, fmt.Errorf("%w: unable to parse %s %s", err, port.key, portValue)
+		}
+		if parsed <= 0 || parsed > 65535 {
+			return nil, fmt.Errorf("%d is not a valid %s", parsed, port.key)
+		}
+		*port.value = parsed
+	}
+
+	if modules := s.get(GviteModulesEnv); len(modules) > 0 {
+		config.PublicModules = splitList(modules)
+		for _, required := range requiredGviteModules {
+			if !contains(config.PublicModules, required) {
+				return nil, fmt.Errorf("%s must contain %s", GviteModulesEnv, required)
+			}
+		}
+	}
+
+	if bootnodes := s.get(GviteBootnodesEnv); len(bootnodes) > 0 {
+		config.BootSeeds = splitList(bootnodes)
+	}
+
+	if logLevel := s.get(GviteLogLevelEnv); len(logLevel) > 0 {
+		config.LogLevel = logLevel
+	}
+
+	return config, nil
+}
+
+// loadGviteFiles sets the location of the gvite binary
+// and of the gvite config template.
+func loadGviteFiles(s settings, config *Configuration) error {
+	config.GviteBinary = vite.DefaultGviteBinary
+	if binary := s.get(GviteBinaryEnv); len(binary) > 0 {
+		config.GviteBinary = binary
+	}
+
+	config.GviteConfigTemplate = s.get(GviteConfigTemplateEnv)
+	if len(config.GviteConfigTemplate) > 0 {
+		if _, err := os.Stat(config.GviteConfigTemplate); err != nil {
+			return fmt.Errorf("%w: unable to read %s %s", err, GviteConfigTemplateEnv, config.GviteConfigTemplate)
+		}
+	}
+
+	return nil
+}
+
+// splitList splits a comma separated list and trims its values.
+func splitList(value string) []string {
+	values := []string{}
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if len(item) > 0 {
+			values = append(values, item)
+		}
+	}
+
+	return values
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
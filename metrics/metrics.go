@@ -0,0 +1,283 @@
+package metrics
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const (
+	namespace = "rosetta_vite"
+
+	// unmatchedEndpoint is the endpoint label of
+	// requests that do not match any route.
+	unmatchedEndpoint = "unmatched"
+)
+
+var (
+	requests = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "requests_total",
+			Help:      "Number of Rosetta requests by endpoint and HTTP status.",
+		},
+		[]string{"endpoint", "status"},
+	)
+
+	requestDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "request_duration_seconds",
+			Help:      "Latency of Rosetta requests by endpoint.",
+			Buckets:   prometheus.DefBuckets,
+		},
+		[]string{"endpoint"},
+	)
+
+	errorCodes = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "errors_total",
+			Help:      "Number of Rosetta errors by endpoint and error code.",
+		},
+		[]string{"endpoint", "code"},
+	)
+
+	rpcCalls = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "gvite_rpc_calls_total",
+			Help:      "Number of gvite RPC calls by method.",
+		},
+		[]string{"method"},
+	)
+
+	rpcFailures = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "gvite_rpc_failures_total",
+			Help:      "Number of failed gvite RPC calls by method.",
+		},
+		[]string{"method"},
+	)
+
+	rpcDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "gvite_rpc_duration_seconds",
+			Help:      "Latency of gvite RPC calls by method, calls sent in a batch request report the latency of the batch.",
+			Buckets:   prometheus.DefBuckets,
+		},
+		[]string{"method", "batch"},
+	)
+
+	powDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "pow_solve_duration_seconds",
+			Help:      "Time to solve a PoW nonce by solver.",
+			Buckets:   prometheus.ExponentialBuckets(0.01, 2, 14), //nolint:gomnd
+		},
+		[]string{"solver", "result"},
+	)
+
+	snapshotHeight = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "snapshot_height",
+			Help:      "Height of the current snapshot block by network.",
+		},
+		[]string{"network"},
+	)
+
+	syncState = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "sync_state",
+			Help:      "Sync state reported by gvite by network, 2 is synced.",
+		},
+		[]string{"network"},
+	)
+
+	gviteUp = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "gvite_up",
+			Help:      "1 if the supervised gvite process is running by network, 0 otherwise.",
+		},
+		[]string{"network"},
+	)
+
+	gviteRestarts = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "gvite_restarts_total",
+			Help:      "Number of restarts of the supervised gvite process by network.",
+		},
+		[]string{"network"},
+	)
+
+	subscriptionConnected = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "gvite_subscription_connected",
+			Help:      "1 if the gvite WebSocket subscription is connected by network, 0 while polling.",
+		},
+		[]string{"network"},
+	)
+
+	synced = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "synced",
+			Help:      "1 if gvite is synced by network, 0 otherwise.",
+		},
+		[]string{"network"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(
+		requests,
+		requestDuration,
+		errorCodes,
+		rpcCalls,
+		rpcFailures,
+		rpcDuration,
+		powDuration,
+		snapshotHeight,
+		syncState,
+		synced,
+		gviteUp,
+		gviteRestarts,
+		subscriptionConnected,
+	)
+}
+
+// Handler returns the http.Handler serving /metrics.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// ObserveRPCCall records a gvite RPC call.
+func ObserveRPCCall(method string, start time.Time, err error) {
+	rpcCalls.WithLabelValues(method).Inc()
+	rpcDuration.WithLabelValues(method, "false").Observe(time.Since(start).Seconds())
+	if err != nil {
+		rpcFailures.WithLabelValues(method).Inc()
+	}
+}
+
+// ObserveRPCBatchCall records the calls of a gvite batch request,
+// callErrors are the errors of the individual calls. Every call is
+// recorded under its method with the latency of the whole batch.
+func ObserveRPCBatchCall(methods []string, start time.Time, err error, callErrors []error) {
+	duration := time.Since(start).Seconds()
+	for i, method := range methods {
+		rpcCalls.WithLabelValues(method).Inc()
+		rpcDuration.WithLabelValues(method, "true").Observe(duration)
+		if err != nil || callErrors[i] != nil {
+			rpcFailures.WithLabelValues(method).Inc()
+		}
+	}
+}
+
+// ObservePoW records the time taken to solve a PoW nonce.
+func ObservePoW(solver string, start time.Time, err error) {
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+	powDuration.WithLabelValues(solver, result).Observe(time.Since(start).Seconds())
+}
+
+// SetNetworkStatus records the current snapshot
+// height and the sync state of a network.
+func SetNetworkStatus(network string, height int64, state uint, isSynced bool) {
+	snapshotHeight.WithLabelValues(network).Set(float64(height))
+	syncState.WithLabelValues(network).Set(float64(state))
+	if isSynced {
+		synced.WithLabelValues(network).Set(1)
+	} else {
+		synced.WithLabelValues(network).Set(0)
+	}
+}
+
+// SetGviteRunning records if the supervised
+// gvite process of a network is running.
+func SetGviteRunning(network string, running bool) {
+	if running {
+		gviteUp.WithLabelValues(network).Set(1)
+	} else {
+		gviteUp.WithLabelValues(network).Set(0)
+	}
+}
+
+// ObserveGviteRestart records a restart of the
+// supervised gvite process of a network.
+func ObserveGviteRestart(network string) {
+	gviteRestarts.WithLabelValues(network).Inc()
+}
+
+// SetSubscriptionConnected records if the gvite
+// WebSocket subscription of a network is connected.
+func SetSubscriptionConnected(network string, connected bool) {
+	if connected {
+		subscriptionConnected.WithLabelValues(network).Set(1)
+	} else {
+		subscriptionConnected.WithLabelValues(network).Set(0)
+	}
+}
+
+// Middleware records the count and latency of every request and
+// the code of every returned types.Error.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		recorder := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+
+		next.ServeHTTP(recorder, r)
+
+		endpoint := r.URL.Path
+		if recorder.status == http.StatusNotFound || recorder.status == http.StatusMethodNotAllowed {
+			endpoint = unmatchedEndpoint
+		}
+
+		requests.WithLabelValues(endpoint, strconv.Itoa(recorder.status)).Inc()
+		requestDuration.WithLabelValues(endpoint).Observe(time.Since(start).Seconds())
+
+		if recorder.status != http.StatusOK && recorder.body.Len() > 0 {
+			rosettaErr := &types.Error{}
+			if err := json.Unmarshal(recorder.body.Bytes(), rosettaErr); err == nil && len(rosettaErr.Message) > 0 {
+				errorCodes.WithLabelValues(endpoint, strconv.Itoa(int(rosettaErr.Code))).Inc()
+			}
+		}
+	})
+}
+
+// responseRecorder records the status of a response and
+// the body of error responses.
+type responseRecorder struct {
+	http.ResponseWriter
+
+	status int
+	body   bytes.Buffer
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	if r.status != http.StatusOK {
+		r.body.Write(b)
+	}
+	return r.ResponseWriter.Write(b)
+}
@@ -2,26 +2,28 @@ package services
 
 import (
 	"context"
+	"errors"
 
 	"github.com/azbuky/rosetta-vite/configuration"
+	"github.com/azbuky/rosetta-vite/vite"
 
 	"github.com/coinbase/rosetta-sdk-go/types"
 )
 
 // AccountAPIService implements the server.AccountAPIServicer interface.
 type AccountAPIService struct {
-	config *configuration.Configuration
-	client Client
+	config  *configuration.Configuration
+	clients Clients
 }
 
 // NewAccountAPIService returns a new *AccountAPIService.
 func NewAccountAPIService(
 	cfg *configuration.Configuration,
-	client Client,
+	clients Clients,
 ) *AccountAPIService {
 	return &AccountAPIService{
-		config: cfg,
-		client: client,
+		config:  cfg,
+		clients: clients,
 	}
 }
 
@@ -34,12 +36,20 @@ func (s *AccountAPIService) AccountBalance(
 		return nil, ErrUnavailableOffline
 	}
 
-	balanceResponse, err := s.client.Balance(
+	client, clientErr := s.clients.get(request.NetworkIdentifier)
+	if clientErr != nil {
+		return nil, clientErr
+	}
+
+	balanceResponse, err := client.Balance(
 		ctx,
 		request.AccountIdentifier,
 		request.Currencies,
 		request.BlockIdentifier,
 	)
+	if errors.Is(err, vite.ErrCurrencyInvalid) {
+		return nil, wrapErr(ErrCurrencyInvalid, err)
+	}
 	if err != nil {
 		return nil, wrapErr(ErrGvite, err)
 	}
@@ -2,26 +2,28 @@ package services
 
 import (
 	"context"
+	"errors"
 
 	"github.com/azbuky/rosetta-vite/configuration"
+	"github.com/azbuky/rosetta-vite/vite"
 
 	"github.com/coinbase/rosetta-sdk-go/types"
 )
 
 // BlockAPIService implements the server.BlockAPIServicer interface.
 type BlockAPIService struct {
-	config *configuration.Configuration
-	client Client
+	config  *configuration.Configuration
+	clients Clients
 }
 
 // NewBlockAPIService creates a new instance of a BlockAPIService.
 func NewBlockAPIService(
 	cfg *configuration.Configuration,
-	client Client,
+	clients Clients,
 ) *BlockAPIService {
 	return &BlockAPIService{
-		config: cfg,
-		client: client,
+		config:  cfg,
+		clients: clients,
 	}
 }
 
@@ -34,7 +36,18 @@ func (s *BlockAPIService) Block(
 		return nil, ErrUnavailableOffline
 	}
 
-	block, transactions, err := s.client.Block(ctx, request.BlockIdentifier)
+	client, clientErr := s.clients.get(request.NetworkIdentifier)
+	if clientErr != nil {
+		return nil, clientErr
+	}
+
+	block, transactions, err := client.Block(ctx, request.BlockIdentifier)
+	if errors.Is(err, vite.ErrBlockOrphaned) {
+		return nil, wrapErr(ErrBlockOrphaned, err)
+	}
+	if errors.Is(err, vite.ErrSnapshotRolledBack) {
+		return nil, wrapErr(ErrSnapshotRolledBack, err)
+	}
 	if err != nil {
 		return nil, wrapErr(ErrGvite, err)
 	}
@@ -54,7 +67,12 @@ func (s *BlockAPIService) BlockTransaction(
 		return nil, ErrUnavailableOffline
 	}
 
-	transaction, err := s.client.BlockTransaction(ctx, request)
+	client, clientErr := s.clients.get(request.NetworkIdentifier)
+	if clientErr != nil {
+		return nil, clientErr
+	}
+
+	transaction, err := client.BlockTransaction(ctx, request)
 	if err != nil {
 		return nil, wrapErr(ErrGvite, err)
 	}
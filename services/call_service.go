@@ -0,0 +1,62 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/azbuky/rosetta-vite/configuration"
+	"github.com/azbuky/rosetta-vite/vite"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// CallAPIService implements the server.CallAPIServicer interface.
+type CallAPIService struct {
+	config  *configuration.Configuration
+	clients Clients
+}
+
+// NewCallAPIService creates a new instance of a CallAPIService.
+func NewCallAPIService(
+	cfg *configuration.Configuration,
+	clients Clients,
+) *CallAPIService {
+	return &CallAPIService{
+		config:  cfg,
+		clients: clients,
+	}
+}
+
+// Call implements the /call endpoint.
+func (s *CallAPIService) Call(
+	ctx context.Context,
+	request *types.CallRequest,
+) (*types.CallResponse, *types.Error) {
+	if s.config.Mode != configuration.Online {
+		return nil, ErrUnavailableOffline
+	}
+
+	client, clientErr := s.clients.get(request.NetworkIdentifier)
+	if clientErr != nil {
+		return nil, clientErr
+	}
+
+	result, idempotent, err := client.Call(ctx, request.Method, request.Parameters)
+	if errors.Is(err, vite.ErrCallParametersInvalid) {
+		return nil, wrapErr(ErrCallParametersInvalid, err)
+	}
+	if errors.Is(err, vite.ErrCallOutputMarshal) {
+		return nil, wrapErr(ErrCallOutputMarshal, err)
+	}
+	if errors.Is(err, vite.ErrCallMethodInvalid) {
+		return nil, wrapErr(ErrCallMethodInvalid, err)
+	}
+	if err != nil {
+		return nil, wrapErr(ErrGvite, err)
+	}
+
+	return &types.CallResponse{
+		Result:     result,
+		Idempotent: idempotent,
+	}, nil
+}
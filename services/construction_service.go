@@ -17,18 +17,18 @@ import (
 
 // ConstructionAPIService implements the server.ConstructionAPIServicer interface.
 type ConstructionAPIService struct {
-	config *configuration.Configuration
-	client Client
+	config  *configuration.Configuration
+	clients Clients
 }
 
 // NewConstructionAPIService creates a new instance of a ConstructionAPIService.
 func NewConstructionAPIService(
 	cfg *configuration.Configuration,
-	client Client,
+	clients Clients,
 ) *ConstructionAPIService {
 	return &ConstructionAPIService{
-		config: cfg,
-		client: client,
+		config:  cfg,
+		clients: clients,
 	}
 }
 
@@ -77,12 +77,17 @@ func (s *ConstructionAPIService) ConstructionMetadata(
 		return nil, ErrUnavailableOffline
 	}
 
+	client, clientErr := s.clients.get(request.NetworkIdentifier)
+	if clientErr != nil {
+		return nil, clientErr
+	}
+
 	var options vite.ConstructionOptions
 	if err := utils.UnmarshalJSONMap(request.Options, &options); err != nil {
 		return nil, wrapErr(ErrUnableToParseIntermediateResult, err)
 	}
 
-	metadata, err := s.client.ConstructionMetadata(ctx, &options)
+	metadata, err := client.ConstructionMetadata(ctx, &options)
 	if err != nil {
 		return nil, wrapErr(ErrUnableToParseIntermediateResult, err)
 	}
@@ -135,7 +140,10 @@ func (s *ConstructionAPIService) ConstructionPayloads(
 		SignatureType:     types.Ed25519,
 	}
 
-	unsignedTransaction, err := utils.EncodeAccountBlockToBase64(*accountBlock)
+	unsignedTransaction, err := utils.EncodeTransactionToBase64(utils.Transaction{
+		AccountBlock: *accountBlock,
+		ContractAbi:  metadata.ContractAbi,
+	})
 	if err != nil {
 		return nil, wrapErr(ErrUnableToParseIntermediateResult, err)
 	}
@@ -152,10 +160,11 @@ func (s *ConstructionAPIService) ConstructionCombine(
 	ctx context.Context,
 	request *types.ConstructionCombineRequest,
 ) (*types.ConstructionCombineResponse, *types.Error) {
-	accountBlock, err := utils.DecodeAccountBlockFromBase64(request.UnsignedTransaction)
+	transaction, err := utils.DecodeTransactionFromBase64(request.UnsignedTransaction)
 	if err != nil {
 		return nil, wrapErr(ErrUnableToParseIntermediateResult, err)
 	}
+	accountBlock := &transaction.AccountBlock
 
 	// Compute hash for block
 	hash, err := accountBlock.ComputeHash()
@@ -176,7 +185,7 @@ func (s *ConstructionAPIService) ConstructionCombine(
 	accountBlock.PublicKey = signature.PublicKey.Bytes
 	accountBlock.Hash = *hash
 
-	signedTransaction, err := utils.EncodeAccountBlockToBase64(*accountBlock)
+	signedTransaction, err := utils.EncodeTransactionToBase64(*transaction)
 	if err != nil {
 		return nil, wrapErr(ErrUnableToParseIntermediateResult, err)
 	}
@@ -211,12 +220,13 @@ func (s *ConstructionAPIService) ConstructionParse(
 	request *types.ConstructionParseRequest,
 ) (*types.ConstructionParseResponse, *types.Error) {
 
-	accountBlock, err := utils.DecodeAccountBlockFromBase64(request.Transaction)
+	transaction, err := utils.DecodeTransactionFromBase64(request.Transaction)
 	if err != nil {
 		return nil, wrapErr(ErrUnableToParseIntermediateResult, err)
 	}
+	accountBlock := &transaction.AccountBlock
 
-	ops, err := vite.OperationsForAccountBlock(accountBlock, false)
+	ops, err := vite.ParseOperationsForAccountBlock(accountBlock)
 	if err != nil {
 		return nil, wrapErr(ErrUnableToParseIntermediateResult, err)
 	}
@@ -233,6 +243,16 @@ func (s *ConstructionAPIService) ConstructionParse(
 	if ledger.IsReceiveBlock(accountBlock.BlockType) {
 		metadata["sendBlockHash"] = accountBlock.SendBlockHash
 	}
+	if accountBlock.BlockType == ledger.BlockTypeSendCreate {
+		metadata["contractAddress"] = accountBlock.ToAddress.Hex()
+	}
+	if len(transaction.ContractAbi) > 0 && accountBlock.BlockType == ledger.BlockTypeSendCall {
+		contractCall, err := vite.DecodeContractCall(transaction.ContractAbi, accountBlock.Data)
+		if err != nil {
+			return nil, wrapErr(ErrUnableToParseIntermediateResult, err)
+		}
+		metadata["contractCall"] = contractCall
+	}
 
 	resp := &types.ConstructionParseResponse{
 		Operations: ops,
@@ -257,6 +277,11 @@ func (s *ConstructionAPIService) ConstructionSubmit(
 		return nil, ErrUnavailableOffline
 	}
 
+	client, clientErr := s.clients.get(request.NetworkIdentifier)
+	if clientErr != nil {
+		return nil, clientErr
+	}
+
 	accountBlock, err := utils.DecodeAccountBlockFromBase64(request.SignedTransaction)
 	if err != nil {
 		return nil, wrapErr(ErrUnableToParseIntermediateResult, err)
@@ -269,7 +294,7 @@ func (s *ConstructionAPIService) ConstructionSubmit(
 		accountBlock.ToAddress = viteTypes.ZERO_ADDRESS
 	}
 
-	if err := s.client.SendTransaction(ctx, accountBlock); err != nil {
+	if err := client.SendTransaction(ctx, accountBlock); err != nil {
 		return nil, wrapErr(ErrBroadcastFailed, err)
 	}
 
@@ -17,8 +17,20 @@ var (
 		ErrSignatureInvalid,
 		ErrBroadcastFailed,
 		ErrCallParametersInvalid,
+		ErrCallOutputMarshal,
+		ErrCallMethodInvalid,
+		ErrSnapshotRolledBack,
 		ErrInvalidAddress,
 		ErrGviteNotReady,
+		ErrTransactionNotFound,
+		ErrIndexerDisabled,
+		ErrEventsDisabled,
+		ErrNetworkNotFound,
+		ErrBlockOrphaned,
+		ErrIndexStorage,
+		ErrCurrencyInvalid,
+		ErrSearchRequestInvalid,
+		ErrInvalidPublicKey,
 	}
 
 	// ErrUnimplemented is returned when an endpoint
@@ -88,6 +100,28 @@ var (
 		Message: "Call parameters invalid",
 	}
 
+	// ErrCallOutputMarshal is returned when the output
+	// for /call cannot be marshaled.
+	ErrCallOutputMarshal = &types.Error{
+		Code:    9, //nolint
+		Message: "Call output marshal failed",
+	}
+
+	// ErrCallMethodInvalid is returned when a /call
+	// method is invalid.
+	ErrCallMethodInvalid = &types.Error{
+		Code:    10, //nolint
+		Message: "Call method invalid",
+	}
+
+	// ErrSnapshotRolledBack is returned when the snapshot
+	// chain rolled back while a block was assembled.
+	ErrSnapshotRolledBack = &types.Error{
+		Code:      11, //nolint
+		Message:   "Snapshot chain rolled back",
+		Retriable: true,
+	}
+
 	// ErrInvalidAddress is returned when an address
 	// is not valid.
 	ErrInvalidAddress = &types.Error{
@@ -102,6 +136,71 @@ var (
 		Message:   "gvite not ready",
 		Retriable: true,
 	}
+
+	// ErrTransactionNotFound is returned when a
+	// transaction cannot be found or is no longer
+	// pending.
+	ErrTransactionNotFound = &types.Error{
+		Code:    14, //nolint
+		Message: "Transaction not found",
+	}
+
+	// ErrIndexerDisabled is returned when an endpoint
+	// requiring the transaction index is called while
+	// the indexer is disabled.
+	ErrIndexerDisabled = &types.Error{
+		Code:    15, //nolint
+		Message: "Transaction indexer disabled",
+	}
+
+	// ErrEventsDisabled is returned when /events/blocks
+	// is called while block event tracking is disabled.
+	ErrEventsDisabled = &types.Error{
+		Code:    16, //nolint
+		Message: "Block events disabled",
+	}
+
+	// ErrNetworkNotFound is returned when a request
+	// targets a network that is not served.
+	ErrNetworkNotFound = &types.Error{
+		Code:    17, //nolint
+		Message: "Network not found",
+	}
+
+	// ErrBlockOrphaned is returned when a block requested
+	// by hash is no longer part of the snapshot chain.
+	ErrBlockOrphaned = &types.Error{
+		Code:    18, //nolint
+		Message: "Block orphaned",
+	}
+
+	// ErrIndexStorage is returned when the transaction
+	// index cannot be read.
+	ErrIndexStorage = &types.Error{
+		Code:    19, //nolint
+		Message: "Unable to read transaction index",
+	}
+
+	// ErrCurrencyInvalid is returned when a requested
+	// currency has no valid tti metadata.
+	ErrCurrencyInvalid = &types.Error{
+		Code:    20, //nolint
+		Message: "Invalid currency",
+	}
+
+	// ErrSearchRequestInvalid is returned when a
+	// /search/transactions request is invalid.
+	ErrSearchRequestInvalid = &types.Error{
+		Code:    21, //nolint
+		Message: "Invalid search request",
+	}
+
+	// ErrInvalidPublicKey is returned when a public key
+	// is not valid or does not match the account.
+	ErrInvalidPublicKey = &types.Error{
+		Code:    22, //nolint
+		Message: "Invalid public key",
+	}
 )
 
 // wrapErr adds details to the types.Error provided. We use a function
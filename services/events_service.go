@@ -0,0 +1,52 @@
+package services
+
+import (
+	"context"
+
+	"github.com/azbuky/rosetta-vite/configuration"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// EventsAPIService implements the server.EventsAPIServicer interface.
+type EventsAPIService struct {
+	config   *configuration.Configuration
+	trackers Trackers
+}
+
+// NewEventsAPIService creates a new instance of an EventsAPIService.
+func NewEventsAPIService(
+	cfg *configuration.Configuration,
+	trackers Trackers,
+) *EventsAPIService {
+	return &EventsAPIService{
+		config:   cfg,
+		trackers: trackers,
+	}
+}
+
+// EventsBlocks implements the /events/blocks endpoint.
+func (s *EventsAPIService) EventsBlocks(
+	ctx context.Context,
+	request *types.EventsBlocksRequest,
+) (*types.EventsBlocksResponse, *types.Error) {
+	if s.config.Mode != configuration.Online {
+		return nil, ErrUnavailableOffline
+	}
+
+	if !s.config.Events {
+		return nil, ErrEventsDisabled
+	}
+
+	tracker, trackerErr := s.trackers.get(request.NetworkIdentifier)
+	if trackerErr != nil {
+		return nil, trackerErr
+	}
+
+	response, err := tracker.EventsBlocks(ctx, request.Offset, request.Limit)
+	if err != nil {
+		return nil, wrapErr(ErrUnableToParseIntermediateResult, err)
+	}
+
+	return response, nil
+}
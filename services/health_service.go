@@ -0,0 +1,136 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/azbuky/rosetta-vite/configuration"
+	"github.com/azbuky/rosetta-vite/vite"
+
+	"github.com/coinbase/rosetta-sdk-go/server"
+)
+
+// LivenessResponse is the response of the /health/live endpoint.
+type LivenessResponse struct {
+	Live bool `json:"live"`
+}
+
+// ReadinessResponse is the response of the /health/ready endpoint,
+// it contains the readiness checks of every network. Networks is
+// empty in offline mode, where no gvite node is required.
+type ReadinessResponse struct {
+	Ready    bool                       `json:"ready"`
+	Networks map[string]*vite.Readiness `json:"networks"`
+}
+
+// HealthAPIService implements the /health endpoints.
+type HealthAPIService struct {
+	config  *configuration.Configuration
+	clients Clients
+}
+
+// NewHealthAPIService creates a new instance of a HealthAPIService.
+func NewHealthAPIService(
+	cfg *configuration.Configuration,
+	clients Clients,
+) *HealthAPIService {
+	return &HealthAPIService{
+		config:  cfg,
+		clients: clients,
+	}
+}
+
+// Live implements the /health/live endpoint.
+func (s *HealthAPIService) Live(ctx context.Context) *LivenessResponse {
+	return &LivenessResponse{Live: true}
+}
+
+// Ready implements the /health/ready endpoint. The
+// networks are checked concurrently, each within
+// the configured health timeout.
+func (s *HealthAPIService) Ready(ctx context.Context) *ReadinessResponse {
+	response := &ReadinessResponse{
+		Ready:    true,
+		Networks: map[string]*vite.Readiness{},
+	}
+	if s.config.Mode != configuration.Online {
+		return response
+	}
+
+	thresholds := &vite.HealthThresholds{
+		MaxBlockAge: s.config.HealthMaxBlockAge,
+		MaxSyncLag:  s.config.HealthMaxSyncLag,
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for network, client := range s.clients {
+		wg.Add(1)
+		go func(network string, client Client) {
+			defer wg.Done()
+
+			checkCtx, cancel := context.WithTimeout(ctx, s.config.HealthTimeout)
+			defer cancel()
+			readiness := client.Readiness(checkCtx, thresholds)
+
+			mu.Lock()
+			defer mu.Unlock()
+			response.Networks[network] = readiness
+			response.Ready = response.Ready && readiness.Ready
+		}(network, client)
+	}
+	wg.Wait()
+
+	return response
+}
+
+// HealthAPIController binds the /health
+// endpoints to a HealthAPIService.
+type HealthAPIController struct {
+	service *HealthAPIService
+}
+
+// NewHealthAPIController creates a HealthAPIController.
+func NewHealthAPIController(s *HealthAPIService) server.Router {
+	return &HealthAPIController{
+		service: s,
+	}
+}
+
+// Routes returns all of the api routes for the HealthAPIController
+func (c *HealthAPIController) Routes() server.Routes {
+	return server.Routes{
+		{
+			Name:        "HealthLive",
+			Method:      strings.ToUpper("Get"),
+			Pattern:     "/health/live",
+			HandlerFunc: c.Live,
+		},
+		{
+			Name:        "HealthReady",
+			Method:      strings.ToUpper("Get"),
+			Pattern:     "/health/ready",
+			HandlerFunc: c.Ready,
+		},
+	}
+}
+
+// Live - Check that rosetta-vite is running
+func (c *HealthAPIController) Live(w http.ResponseWriter, r *http.Request) {
+	server.EncodeJSONResponse(c.service.Live(r.Context()), http.StatusOK, w)
+}
+
+// Ready - Check that every network can serve requests, responds
+// with 503 Service Unavailable if any check fails
+func (c *HealthAPIController) Ready(w http.ResponseWriter, r *http.Request) {
+	result := c.service.Ready(r.Context())
+	if !result.Ready {
+		server.EncodeJSONResponse(result, http.StatusServiceUnavailable, w)
+
+		return
+	}
+
+	server.EncodeJSONResponse(result, http.StatusOK, w)
+}
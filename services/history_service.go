@@ -0,0 +1,225 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/azbuky/rosetta-vite/configuration"
+	"github.com/azbuky/rosetta-vite/vite"
+
+	"github.com/coinbase/rosetta-sdk-go/asserter"
+	"github.com/coinbase/rosetta-sdk-go/server"
+	"github.com/coinbase/rosetta-sdk-go/types"
+	viteTypes "github.com/vitelabs/go-vite/common/types"
+)
+
+// AccountTransactionsRequest is the request of the
+// /account/transactions endpoint. The page starts at the
+// block with Height or Hash, or at the latest block if
+// neither is set. If Currency is set only transactions
+// of its token are returned.
+type AccountTransactionsRequest struct {
+	NetworkIdentifier *types.NetworkIdentifier `json:"network_identifier"`
+	AccountIdentifier *types.AccountIdentifier `json:"account_identifier"`
+	Height            *int64                   `json:"height,omitempty"`
+	Hash              *string                  `json:"hash,omitempty"`
+	Currency          *types.Currency          `json:"currency,omitempty"`
+	Limit             *int64                   `json:"limit,omitempty"`
+}
+
+// AccountTransactionsResponse is the response of the /account/transactions
+// endpoint. NextHeight and NextHash are the start of the next page,
+// they are omitted once the beginning of the account chain is reached.
+type AccountTransactionsResponse struct {
+	Transactions []*types.Transaction `json:"transactions"`
+	NextHeight   *int64               `json:"next_height,omitempty"`
+	NextHash     *string              `json:"next_hash,omitempty"`
+}
+
+// HistoryAPIService implements the /account/transactions endpoint.
+type HistoryAPIService struct {
+	config  *configuration.Configuration
+	clients Clients
+}
+
+// NewHistoryAPIService creates a new instance of a HistoryAPIService.
+func NewHistoryAPIService(
+	cfg *configuration.Configuration,
+	clients Clients,
+) *HistoryAPIService {
+	return &HistoryAPIService{
+		config:  cfg,
+		clients: clients,
+	}
+}
+
+// AccountTransactions implements the /account/transactions endpoint.
+func (s *HistoryAPIService) AccountTransactions(
+	ctx context.Context,
+	request *AccountTransactionsRequest,
+) (*AccountTransactionsResponse, *types.Error) {
+	if s.config.Mode != configuration.Online {
+		return nil, ErrUnavailableOffline
+	}
+
+	client, clientErr := s.clients.get(request.NetworkIdentifier)
+	if clientErr != nil {
+		return nil, clientErr
+	}
+
+	if _, err := viteTypes.HexToAddress(request.AccountIdentifier.Address); err != nil {
+		return nil, wrapErr(ErrInvalidAddress, err)
+	}
+
+	var height *uint64
+	if request.Height != nil {
+		value := uint64(*request.Height)
+		height = &value
+	}
+
+	var hash *viteTypes.Hash
+	if request.Hash != nil {
+		value, err := viteTypes.HexToHash(*request.Hash)
+		if err != nil {
+			return nil, wrapErr(ErrCallParametersInvalid, err)
+		}
+		hash = &value
+	}
+
+	var tokenId *viteTypes.TokenTypeId
+	if request.Currency != nil {
+		tti, ok := request.Currency.Metadata["tti"].(string)
+		if !ok {
+			return nil, wrapErr(ErrCallParametersInvalid, fmt.Errorf("currency metadata must contain tti"))
+		}
+		value, err := viteTypes.HexToTokenTypeId(tti)
+		if err != nil {
+			return nil, wrapErr(ErrCallParametersInvalid, err)
+		}
+		tokenId = &value
+	}
+
+	limit := vite.DefaultHistoryLimit
+	if request.Limit != nil {
+		limit = *request.Limit
+	}
+
+	history, err := client.AccountHistory(
+		ctx,
+		request.AccountIdentifier,
+		height,
+		hash,
+		tokenId,
+		limit,
+	)
+	if err != nil {
+		return nil, wrapErr(ErrGvite, err)
+	}
+
+	response := &AccountTransactionsResponse{
+		Transactions: history.Transactions,
+	}
+	if history.NextHeight != nil {
+		nextHeight := int64(*history.NextHeight)
+		response.NextHeight = &nextHeight
+	}
+	if history.NextHash != nil {
+		nextHash := history.NextHash.Hex()
+		response.NextHash = &nextHash
+	}
+
+	return response, nil
+}
+
+// HistoryAPIController binds the /account/transactions
+// endpoint to a HistoryAPIService.
+type HistoryAPIController struct {
+	service  *HistoryAPIService
+	asserter *asserter.Asserter
+}
+
+// NewHistoryAPIController creates a HistoryAPIController.
+func NewHistoryAPIController(
+	s *HistoryAPIService,
+	asserter *asserter.Asserter,
+) server.Router {
+	return &HistoryAPIController{
+		service:  s,
+		asserter: asserter,
+	}
+}
+
+// Routes returns all of the api routes for the HistoryAPIController
+func (c *HistoryAPIController) Routes() server.Routes {
+	return server.Routes{
+		{
+			Name:        "AccountTransactions",
+			Method:      strings.ToUpper("Post"),
+			Pattern:     "/account/transactions",
+			HandlerFunc: c.AccountTransactions,
+		},
+	}
+}
+
+// AccountTransactions - Get a page of the transactions of an account
+func (c *HistoryAPIController) AccountTransactions(w http.ResponseWriter, r *http.Request) {
+	request := &AccountTransactionsRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		server.EncodeJSONResponse(&types.Error{
+			Message: err.Error(),
+		}, http.StatusInternalServerError, w)
+
+		return
+	}
+
+	if err := c.assertRequest(request); err != nil {
+		server.EncodeJSONResponse(&types.Error{
+			Message: err.Error(),
+		}, http.StatusInternalServerError, w)
+
+		return
+	}
+
+	result, serviceErr := c.service.AccountTransactions(r.Context(), request)
+	if serviceErr != nil {
+		server.EncodeJSONResponse(serviceErr, http.StatusInternalServerError, w)
+
+		return
+	}
+
+	server.EncodeJSONResponse(result, http.StatusOK, w)
+}
+
+// assertRequest ensures an AccountTransactionsRequest is valid.
+func (c *HistoryAPIController) assertRequest(request *AccountTransactionsRequest) error {
+	if err := c.asserter.ValidSupportedNetwork(request.NetworkIdentifier); err != nil {
+		return err
+	}
+
+	if err := asserter.AccountIdentifier(request.AccountIdentifier); err != nil {
+		return err
+	}
+
+	if request.Height != nil && *request.Height < 1 {
+		return fmt.Errorf("height must be positive")
+	}
+
+	if request.Height != nil && request.Hash != nil {
+		return fmt.Errorf("only one of height and hash can be set")
+	}
+
+	if request.Currency != nil {
+		if err := asserter.Currency(request.Currency); err != nil {
+			return err
+		}
+	}
+
+	if request.Limit != nil && (*request.Limit < 1 || *request.Limit > vite.MaxHistoryLimit) {
+		return fmt.Errorf("limit must be between 1 and %d", vite.MaxHistoryLimit)
+	}
+
+	return nil
+}
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/azbuky/rosetta-vite/configuration"
+	"github.com/azbuky/rosetta-vite/vite"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// MempoolAPIService implements the server.MempoolAPIServicer interface.
+type MempoolAPIService struct {
+	config  *configuration.Configuration
+	clients Clients
+}
+
+// NewMempoolAPIService creates a new instance of a MempoolAPIService.
+func NewMempoolAPIService(
+	cfg *configuration.Configuration,
+	clients Clients,
+) *MempoolAPIService {
+	return &MempoolAPIService{
+		config:  cfg,
+		clients: clients,
+	}
+}
+
+// Mempool implements the /mempool endpoint.
+func (s *MempoolAPIService) Mempool(
+	ctx context.Context,
+	request *types.NetworkRequest,
+) (*types.MempoolResponse, *types.Error) {
+	if s.config.Mode != configuration.Online {
+		return nil, ErrUnavailableOffline
+	}
+
+	client, clientErr := s.clients.get(request.NetworkIdentifier)
+	if clientErr != nil {
+		return nil, clientErr
+	}
+
+	transactionIdentifiers, err := client.Mempool(ctx)
+	if err != nil {
+		return nil, wrapErr(ErrGvite, err)
+	}
+
+	return &types.MempoolResponse{
+		TransactionIdentifiers: transactionIdentifiers,
+	}, nil
+}
+
+// MempoolTransaction implements the /mempool/transaction endpoint.
+func (s *MempoolAPIService) MempoolTransaction(
+	ctx context.Context,
+	request *types.MempoolTransactionRequest,
+) (*types.MempoolTransactionResponse, *types.Error) {
+	if s.config.Mode != configuration.Online {
+		return nil, ErrUnavailableOffline
+	}
+
+	client, clientErr := s.clients.get(request.NetworkIdentifier)
+	if clientErr != nil {
+		return nil, clientErr
+	}
+
+	transaction, err := client.MempoolTransaction(ctx, request.TransactionIdentifier)
+	if errors.Is(err, vite.ErrTransactionNotFound) {
+		return nil, wrapErr(ErrTransactionNotFound, err)
+	}
+	if err != nil {
+		return nil, wrapErr(ErrGvite, err)
+	}
+
+	return &types.MempoolTransactionResponse{
+		Transaction: transaction,
+	}, nil
+}
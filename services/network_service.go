@@ -12,18 +12,18 @@ import (
 
 // NetworkAPIService implements the server.NetworkAPIServicer interface.
 type NetworkAPIService struct {
-	config *configuration.Configuration
-	client Client
+	config  *configuration.Configuration
+	clients Clients
 }
 
 // NewNetworkAPIService creates a new instance of a NetworkAPIService.
 func NewNetworkAPIService(
 	cfg *configuration.Configuration,
-	client Client,
+	clients Clients,
 ) *NetworkAPIService {
 	return &NetworkAPIService{
-		config: cfg,
-		client: client,
+		config:  cfg,
+		clients: clients,
 	}
 }
 
@@ -33,7 +33,7 @@ func (s *NetworkAPIService) NetworkList(
 	request *types.MetadataRequest,
 ) (*types.NetworkListResponse, *types.Error) {
 	return &types.NetworkListResponse{
-		NetworkIdentifiers: []*types.NetworkIdentifier{s.config.Network},
+		NetworkIdentifiers: s.config.NetworkIdentifiers(),
 	}, nil
 }
 
@@ -53,7 +53,7 @@ func (s *NetworkAPIService) NetworkOptions(
 			OperationTypes:          vite.OperationTypes,
 			OperationStatuses:       vite.OperationStatuses,
 			HistoricalBalanceLookup: vite.HistoricalBalanceSupported,
-			CallMethods:             vite.CallMethods,
+			CallMethods:             s.config.CallMethods,
 			BalanceExemptions:       []*types.BalanceExemption{},
 			MempoolCoins:            false,
 		},
@@ -69,7 +69,12 @@ func (s *NetworkAPIService) NetworkStatus(
 		return nil, ErrUnavailableOffline
 	}
 
-	currentBlock, currentTime, syncStatus, peers, err := s.client.Status(ctx)
+	client, clientErr := s.clients.get(request.NetworkIdentifier)
+	if clientErr != nil {
+		return nil, clientErr
+	}
+
+	currentBlock, currentTime, syncStatus, peers, err := client.Status(ctx)
 	if err != nil {
 		return nil, wrapErr(ErrGvite, err)
 	}
@@ -78,11 +83,16 @@ func (s *NetworkAPIService) NetworkStatus(
 		return nil, ErrGviteNotReady
 	}
 
+	genesisBlock, err := client.GenesisBlockIdentifier(ctx)
+	if err != nil {
+		return nil, wrapErr(ErrGviteNotReady, err)
+	}
+
 	return &types.NetworkStatusResponse{
 		CurrentBlockIdentifier: currentBlock,
 		CurrentBlockTimestamp:  currentTime,
-		GenesisBlockIdentifier: s.client.GenesisBlockIdentifier(),
-		OldestBlockIdentifier:  s.client.GenesisBlockIdentifier(),
+		GenesisBlockIdentifier: genesisBlock,
+		OldestBlockIdentifier:  genesisBlock,
 		SyncStatus:             syncStatus,
 		Peers:                  peers,
 	}, nil
@@ -0,0 +1,233 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/azbuky/rosetta-vite/configuration"
+	"github.com/azbuky/rosetta-vite/utils"
+	"github.com/azbuky/rosetta-vite/vite"
+
+	"github.com/coinbase/rosetta-sdk-go/asserter"
+	"github.com/coinbase/rosetta-sdk-go/server"
+	"github.com/coinbase/rosetta-sdk-go/types"
+
+	viteTypes "github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/crypto/ed25519"
+)
+
+// ConstructionReceiveRequest is the request of the
+// /construction/receive endpoint. If PublicKey is provided
+// the unsigned transactions are returned along the intents.
+type ConstructionReceiveRequest struct {
+	NetworkIdentifier *types.NetworkIdentifier `json:"network_identifier"`
+	AccountIdentifier *types.AccountIdentifier `json:"account_identifier"`
+	PublicKey         *types.PublicKey         `json:"public_key,omitempty"`
+	Limit             *int64                   `json:"limit,omitempty"`
+}
+
+// ConstructionReceiveResponse is the response of the
+// /construction/receive endpoint.
+type ConstructionReceiveResponse struct {
+	Intents []*ConstructionReceiveIntent `json:"intents"`
+}
+
+// ConstructionReceiveIntent contains the RESPONSE operation receiving
+// an unreceived send block. UnsignedTransaction, Payloads and PowDecision
+// are only set when a public key is provided, each transaction is chained
+// to the previous one and they must be submitted in order. Intents
+// requiring PoW after the first one have no unsigned transaction, they
+// can be constructed once the previous transactions were submitted.
+type ConstructionReceiveIntent struct {
+	Operations          []*types.Operation      `json:"operations"`
+	UnsignedTransaction string                  `json:"unsigned_transaction,omitempty"`
+	Payloads            []*types.SigningPayload `json:"payloads,omitempty"`
+	PowDecision         *vite.PowDecision       `json:"pow_decision,omitempty"`
+}
+
+// ReceiveAPIService implements the /construction/receive endpoint.
+type ReceiveAPIService struct {
+	config  *configuration.Configuration
+	clients Clients
+}
+
+// NewReceiveAPIService creates a new instance of a ReceiveAPIService.
+func NewReceiveAPIService(
+	cfg *configuration.Configuration,
+	clients Clients,
+) *ReceiveAPIService {
+	return &ReceiveAPIService{
+		config:  cfg,
+		clients: clients,
+	}
+}
+
+// ConstructionReceive implements the /construction/receive endpoint.
+func (s *ReceiveAPIService) ConstructionReceive(
+	ctx context.Context,
+	request *ConstructionReceiveRequest,
+) (*ConstructionReceiveResponse, *types.Error) {
+	if s.config.Mode != configuration.Online {
+		return nil, ErrUnavailableOffline
+	}
+
+	client, clientErr := s.clients.get(request.NetworkIdentifier)
+	if clientErr != nil {
+		return nil, clientErr
+	}
+
+	address, err := viteTypes.HexToAddress(request.AccountIdentifier.Address)
+	if err != nil {
+		return nil, wrapErr(ErrInvalidAddress, err)
+	}
+
+	if request.PublicKey != nil {
+		if request.PublicKey.CurveType != types.Edwards25519 ||
+			len(request.PublicKey.Bytes) != ed25519.PublicKeySize {
+			return nil, wrapErr(
+				ErrInvalidPublicKey,
+				fmt.Errorf("expected a %s public key", types.Edwards25519),
+			)
+		}
+		if viteTypes.PubkeyToAddress(request.PublicKey.Bytes) != address {
+			return nil, wrapErr(
+				ErrInvalidPublicKey,
+				fmt.Errorf("public key does not match %s", request.AccountIdentifier.Address),
+			)
+		}
+	}
+
+	limit := int64(0)
+	if request.Limit != nil {
+		limit = *request.Limit
+	}
+
+	transactions, err := client.ReceiveTransactions(
+		ctx,
+		request.AccountIdentifier,
+		request.PublicKey,
+		limit,
+	)
+	if err != nil {
+		return nil, wrapErr(ErrGvite, err)
+	}
+
+	intents := []*ConstructionReceiveIntent{}
+	for _, transaction := range transactions {
+		intent := &ConstructionReceiveIntent{
+			Operations:  transaction.Operations,
+			PowDecision: transaction.PowDecision,
+		}
+		intents = append(intents, intent)
+
+		accountBlock := transaction.AccountBlock
+		if accountBlock == nil {
+			continue
+		}
+
+		unsignedTransaction, err := utils.EncodeTransactionToBase64(utils.Transaction{
+			AccountBlock: *accountBlock,
+		})
+		if err != nil {
+			return nil, wrapErr(ErrUnableToParseIntermediateResult, err)
+		}
+
+		intent.UnsignedTransaction = unsignedTransaction
+		intent.Payloads = []*types.SigningPayload{
+			{
+				AccountIdentifier: request.AccountIdentifier,
+				Bytes:             accountBlock.Hash.Bytes(),
+				SignatureType:     types.Ed25519,
+			},
+		}
+	}
+
+	return &ConstructionReceiveResponse{
+		Intents: intents,
+	}, nil
+}
+
+// ReceiveAPIController binds the /construction/receive
+// endpoint to a ReceiveAPIService.
+type ReceiveAPIController struct {
+	service  *ReceiveAPIService
+	asserter *asserter.Asserter
+}
+
+// NewReceiveAPIController creates a ReceiveAPIController.
+func NewReceiveAPIController(
+	s *ReceiveAPIService,
+	asserter *asserter.Asserter,
+) server.Router {
+	return &ReceiveAPIController{
+		service:  s,
+		asserter: asserter,
+	}
+}
+
+// Routes returns all of the api routes for the ReceiveAPIController
+func (c *ReceiveAPIController) Routes() server.Routes {
+	return server.Routes{
+		{
+			Name:        "ConstructionReceive",
+			Method:      strings.ToUpper("Post"),
+			Pattern:     "/construction/receive",
+			HandlerFunc: c.ConstructionReceive,
+		},
+	}
+}
+
+// ConstructionReceive - Get RESPONSE intents for all unreceived blocks
+func (c *ReceiveAPIController) ConstructionReceive(w http.ResponseWriter, r *http.Request) {
+	request := &ConstructionReceiveRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		server.EncodeJSONResponse(&types.Error{
+			Message: err.Error(),
+		}, http.StatusInternalServerError, w)
+
+		return
+	}
+
+	if err := c.assertRequest(request); err != nil {
+		server.EncodeJSONResponse(&types.Error{
+			Message: err.Error(),
+		}, http.StatusInternalServerError, w)
+
+		return
+	}
+
+	result, serviceErr := c.service.ConstructionReceive(r.Context(), request)
+	if serviceErr != nil {
+		server.EncodeJSONResponse(serviceErr, http.StatusInternalServerError, w)
+
+		return
+	}
+
+	server.EncodeJSONResponse(result, http.StatusOK, w)
+}
+
+// assertRequest ensures a ConstructionReceiveRequest is valid.
+func (c *ReceiveAPIController) assertRequest(request *ConstructionReceiveRequest) error {
+	if err := c.asserter.ValidSupportedNetwork(request.NetworkIdentifier); err != nil {
+		return err
+	}
+
+	if err := asserter.AccountIdentifier(request.AccountIdentifier); err != nil {
+		return err
+	}
+
+	if request.PublicKey != nil {
+		if err := asserter.PublicKey(request.PublicKey); err != nil {
+			return err
+		}
+	}
+
+	if request.Limit != nil && *request.Limit < 0 {
+		return fmt.Errorf("limit must not be negative")
+	}
+
+	return nil
+}
@@ -10,40 +10,89 @@ import (
 )
 
 // NewBlockchainRouter creates a Mux http.Handler from a collection
-// of server controllers.
+// of server controllers. Requests are routed to the client, indexer
+// and tracker of their network.
 func NewBlockchainRouter(
 	config *configuration.Configuration,
-	client Client,
+	clients Clients,
+	indexers Indexers,
+	trackers Trackers,
 	asserter *asserter.Asserter,
 ) http.Handler {
-	networkAPIService := NewNetworkAPIService(config, client)
+	networkAPIService := NewNetworkAPIService(config, clients)
 	networkAPIController := server.NewNetworkAPIController(
 		networkAPIService,
 		asserter,
 	)
 
-	accountAPIService := NewAccountAPIService(config, client)
+	accountAPIService := NewAccountAPIService(config, clients)
 	accountAPIController := server.NewAccountAPIController(
 		accountAPIService,
 		asserter,
 	)
 
-	blockAPIService := NewBlockAPIService(config, client)
+	blockAPIService := NewBlockAPIService(config, clients)
 	blockAPIController := server.NewBlockAPIController(
 		blockAPIService,
 		asserter,
 	)
 
-	constructionAPIService := NewConstructionAPIService(config, client)
+	constructionAPIService := NewConstructionAPIService(config, clients)
 	constructionAPIController := server.NewConstructionAPIController(
 		constructionAPIService,
 		asserter,
 	)
 
+	mempoolAPIService := NewMempoolAPIService(config, clients)
+	mempoolAPIController := server.NewMempoolAPIController(
+		mempoolAPIService,
+		asserter,
+	)
+
+	callAPIService := NewCallAPIService(config, clients)
+	callAPIController := server.NewCallAPIController(
+		callAPIService,
+		asserter,
+	)
+
+	searchAPIService := NewSearchAPIService(config, indexers)
+	searchAPIController := server.NewSearchAPIController(
+		searchAPIService,
+		asserter,
+	)
+
+	eventsAPIService := NewEventsAPIService(config, trackers)
+	eventsAPIController := server.NewEventsAPIController(
+		eventsAPIService,
+		asserter,
+	)
+
+	receiveAPIService := NewReceiveAPIService(config, clients)
+	receiveAPIController := NewReceiveAPIController(
+		receiveAPIService,
+		asserter,
+	)
+
+	historyAPIService := NewHistoryAPIService(config, clients)
+	historyAPIController := NewHistoryAPIController(
+		historyAPIService,
+		asserter,
+	)
+
+	healthAPIService := NewHealthAPIService(config, clients)
+	healthAPIController := NewHealthAPIController(healthAPIService)
+
 	return server.NewRouter(
 		networkAPIController,
 		accountAPIController,
 		blockAPIController,
 		constructionAPIController,
+		mempoolAPIController,
+		callAPIController,
+		searchAPIController,
+		eventsAPIController,
+		receiveAPIController,
+		historyAPIController,
+		healthAPIController,
 	)
 }
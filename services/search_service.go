@@ -0,0 +1,77 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/azbuky/rosetta-vite/configuration"
+	"github.com/azbuky/rosetta-vite/vite"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// SearchAPIService implements the server.SearchAPIServicer interface.
+type SearchAPIService struct {
+	config   *configuration.Configuration
+	indexers Indexers
+}
+
+// NewSearchAPIService creates a new instance of a SearchAPIService.
+func NewSearchAPIService(
+	cfg *configuration.Configuration,
+	indexers Indexers,
+) *SearchAPIService {
+	return &SearchAPIService{
+		config:   cfg,
+		indexers: indexers,
+	}
+}
+
+// SearchTransactions implements the /search/transactions endpoint.
+func (s *SearchAPIService) SearchTransactions(
+	ctx context.Context,
+	request *types.SearchTransactionsRequest,
+) (*types.SearchTransactionsResponse, *types.Error) {
+	if s.config.Mode != configuration.Online {
+		return nil, ErrUnavailableOffline
+	}
+
+	if !s.config.Indexer {
+		return nil, ErrIndexerDisabled
+	}
+
+	if err := validateSearchRequest(request); err != nil {
+		return nil, wrapErr(ErrSearchRequestInvalid, err)
+	}
+
+	indexer, indexerErr := s.indexers.get(request.NetworkIdentifier)
+	if indexerErr != nil {
+		return nil, indexerErr
+	}
+
+	response, err := indexer.Search(ctx, request)
+	if errors.Is(err, vite.ErrSearchRequestInvalid) {
+		return nil, wrapErr(ErrSearchRequestInvalid, err)
+	}
+	if err != nil {
+		return nil, wrapErr(ErrIndexStorage, err)
+	}
+
+	return response, nil
+}
+
+// validateSearchRequest checks the paging of a search request.
+func validateSearchRequest(request *types.SearchTransactionsRequest) error {
+	if request.Limit != nil && (*request.Limit < 1 || *request.Limit > vite.SearchMaxLimit) {
+		return fmt.Errorf("limit must be between 1 and %d", vite.SearchMaxLimit)
+	}
+	if request.Offset != nil && *request.Offset < 0 {
+		return fmt.Errorf("offset must not be negative")
+	}
+	if request.MaxBlock != nil && *request.MaxBlock < 0 {
+		return fmt.Errorf("max block must not be negative")
+	}
+
+	return nil
+}
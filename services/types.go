@@ -2,9 +2,11 @@ package services
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/azbuky/rosetta-vite/vite"
 	"github.com/coinbase/rosetta-sdk-go/types"
+	viteTypes "github.com/vitelabs/go-vite/common/types"
 	"github.com/vitelabs/go-vite/rpcapi/api"
 )
 
@@ -19,7 +21,7 @@ type Client interface {
 		error,
 	)
 
-	GenesisBlockIdentifier() *types.BlockIdentifier
+	GenesisBlockIdentifier(context.Context) (*types.BlockIdentifier, error)
 
 	Block(
 		context.Context,
@@ -44,4 +46,103 @@ type Client interface {
 	) (*vite.ConstructionMetadata, error)
 
 	SendTransaction(context.Context, *api.AccountBlock) error
+
+	Mempool(context.Context) ([]*types.TransactionIdentifier, error)
+
+	Call(
+		context.Context,
+		string,
+		map[string]interface{},
+	) (map[string]interface{}, bool, error)
+
+	MempoolTransaction(
+		context.Context,
+		*types.TransactionIdentifier,
+	) (*types.Transaction, error)
+
+	ReceiveTransactions(
+		context.Context,
+		*types.AccountIdentifier,
+		*types.PublicKey,
+		int64,
+	) ([]*vite.ReceiveTransaction, error)
+
+	AccountHistory(
+		context.Context,
+		*types.AccountIdentifier,
+		*uint64,
+		*viteTypes.Hash,
+		*viteTypes.TokenTypeId,
+		int64,
+	) (*vite.AccountHistory, error)
+
+	Readiness(context.Context, *vite.HealthThresholds) *vite.Readiness
+
+	GviteReady() error
+}
+
+// Indexer is used by the services to search
+// indexed transactions.
+type Indexer interface {
+	Search(
+		context.Context,
+		*types.SearchTransactionsRequest,
+	) (*types.SearchTransactionsResponse, error)
+}
+
+// Tracker is used by the services to get
+// block events.
+type Tracker interface {
+	EventsBlocks(
+		ctx context.Context,
+		offset *int64,
+		limit *int64,
+	) (*types.EventsBlocksResponse, error)
+}
+
+// Clients maps the name of every network
+// served to the Client of that network.
+type Clients map[string]Client
+
+// get returns the Client of a network, or ErrGviteNotReady
+// while the local gvite node of the network is not running.
+func (c Clients) get(network *types.NetworkIdentifier) (Client, *types.Error) {
+	client, ok := c[network.Network]
+	if !ok {
+		return nil, wrapErr(ErrNetworkNotFound, fmt.Errorf("network %s is not configured", network.Network))
+	}
+
+	if err := client.GviteReady(); err != nil {
+		return nil, wrapErr(ErrGviteNotReady, err)
+	}
+
+	return client, nil
+}
+
+// Indexers maps the name of every network
+// served to the Indexer of that network.
+type Indexers map[string]Indexer
+
+// get returns the Indexer of a network.
+func (i Indexers) get(network *types.NetworkIdentifier) (Indexer, *types.Error) {
+	indexer, ok := i[network.Network]
+	if !ok {
+		return nil, wrapErr(ErrNetworkNotFound, fmt.Errorf("network %s is not configured", network.Network))
+	}
+
+	return indexer, nil
+}
+
+// Trackers maps the name of every network
+// served to the Tracker of that network.
+type Trackers map[string]Tracker
+
+// get returns the Tracker of a network.
+func (t Trackers) get(network *types.NetworkIdentifier) (Tracker, *types.Error) {
+	tracker, ok := t[network.Network]
+	if !ok {
+		return nil, wrapErr(ErrNetworkNotFound, fmt.Errorf("network %s is not configured", network.Network))
+	}
+
+	return tracker, nil
 }
@@ -57,3 +57,34 @@ func EncodeAccountBlockToBase64(accountBlock api.AccountBlock) (string, error) {
 	data := base64.StdEncoding.EncodeToString(jsonData)
 	return data, nil
 }
+
+// Defines the unsigned and signed transaction format. The contract
+// abi is carried along the account block so that the transaction
+// data can be decoded by /construction/parse.
+type Transaction struct {
+	api.AccountBlock
+	ContractAbi string `json:"contractAbi,omitempty"`
+}
+
+// Decodes a transaction from a base64 encoded string
+func DecodeTransactionFromBase64(data string) (*Transaction, error) {
+	var transaction Transaction
+	jsonData, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(jsonData, &transaction); err != nil {
+		return nil, err
+	}
+	return &transaction, nil
+}
+
+// Encodes a transaction to a base64 string
+func EncodeTransactionToBase64(transaction Transaction) (string, error) {
+	jsonData, err := json.Marshal(transaction)
+	if err != nil {
+		return "", err
+	}
+	data := base64.StdEncoding.EncodeToString(jsonData)
+	return data, nil
+}
@@ -0,0 +1,210 @@
+package vite
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+
+	viteTypes "github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/vm/abi"
+)
+
+// Defines the decoded contract call reported by /construction/parse
+type ContractCall struct {
+	Method string        `json:"method"`
+	Args   []interface{} `json:"args"`
+}
+
+// ContractAbiFromMetadata returns the contract abi in metadata
+// as a JSON string. The abi can be provided either as a JSON
+// string or as a JSON array.
+func ContractAbiFromMetadata(value interface{}) (string, error) {
+	if abiStr, ok := value.(string); ok {
+		return abiStr, nil
+	}
+
+	abiJSON, err := json.Marshal(value)
+	if err != nil {
+		return "", fmt.Errorf("%w: invalid contract abi", err)
+	}
+	return string(abiJSON), nil
+}
+
+// EncodeContractCall ABI-encodes a call to method with args.
+// An empty method name encodes the constructor arguments.
+func EncodeContractCall(abiStr string, method string, args []interface{}) ([]byte, error) {
+	contract, err := abi.JSONToABIContract(strings.NewReader(abiStr))
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid contract abi", err)
+	}
+
+	inputs := contract.Constructor.Inputs
+	if len(method) > 0 {
+		abiMethod, ok := contract.Methods[method]
+		if !ok {
+			return nil, fmt.Errorf("method %s not found in contract abi", method)
+		}
+		inputs = abiMethod.Inputs
+	}
+
+	arguments, err := abiArguments(args, inputs)
+	if err != nil {
+		return nil, err
+	}
+
+	return contract.PackMethod(method, arguments...)
+}
+
+// DecodeContractCall decodes the method and arguments of
+// contract call data using the provided abi.
+func DecodeContractCall(abiStr string, data []byte) (*ContractCall, error) {
+	contract, err := abi.JSONToABIContract(strings.NewReader(abiStr))
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid contract abi", err)
+	}
+
+	method, err := contract.MethodById(data)
+	if err != nil {
+		return nil, err
+	}
+
+	args, err := method.Inputs.DirectUnpack(data[4:])
+	if err != nil {
+		return nil, err
+	}
+
+	return &ContractCall{
+		Method: method.Name,
+		Args:   args,
+	}, nil
+}
+
+// abiArguments converts JSON values to the Go values
+// expected by the abi arguments.
+func abiArguments(args []interface{}, inputs abi.Arguments) ([]interface{}, error) {
+	if len(args) != len(inputs) {
+		return nil, fmt.Errorf("expected %d arguments, got %d", len(inputs), len(args))
+	}
+
+	arguments := make([]interface{}, len(args))
+	for i, input := range inputs {
+		argument, err := abiArgument(args[i], input.Type)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid argument %s", err, input.Name)
+		}
+		arguments[i] = argument
+	}
+
+	return arguments, nil
+}
+
+// abiArgument converts a single JSON value to the Go value of type t.
+func abiArgument(value interface{}, t abi.Type) (interface{}, error) {
+	switch t.T {
+	case abi.SliceTy, abi.ArrayTy:
+		raw, ok := value.(string)
+		if !ok {
+			encoded, err := json.Marshal(value)
+			if err != nil {
+				return nil, err
+			}
+			raw = string(encoded)
+		}
+		result := reflect.New(t.Type)
+		if err := json.Unmarshal([]byte(raw), result.Interface()); err != nil {
+			return nil, err
+		}
+		return result.Elem().Interface(), nil
+	case abi.BoolTy:
+		switch v := value.(type) {
+		case bool:
+			return v, nil
+		case string:
+			parsed, err := strconv.ParseBool(v)
+			if err != nil {
+				return nil, fmt.Errorf("%s is not a bool", v)
+			}
+			return parsed, nil
+		}
+		return nil, fmt.Errorf("%v is not a bool", value)
+	case abi.IntTy, abi.UintTy:
+		return abiInteger(value, t)
+	}
+
+	str, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("%v is not a string", value)
+	}
+
+	switch t.T {
+	case abi.StringTy:
+		return str, nil
+	case abi.AddressTy:
+		return viteTypes.HexToAddress(str)
+	case abi.GidTy:
+		return viteTypes.HexToGid(str)
+	case abi.TokenIdTy:
+		return viteTypes.HexToTokenTypeId(str)
+	case abi.BytesTy:
+		return hex.DecodeString(strings.TrimPrefix(str, "0x"))
+	case abi.FixedBytesTy:
+		decoded, err := hex.DecodeString(strings.TrimPrefix(str, "0x"))
+		if err != nil {
+			return nil, err
+		}
+		if len(decoded) > t.Size {
+			return nil, fmt.Errorf("%s is longer than %d bytes", str, t.Size)
+		}
+		result := reflect.New(t.Type).Elem()
+		reflect.Copy(result, reflect.ValueOf(decoded))
+		return result.Interface(), nil
+	}
+
+	return nil, fmt.Errorf("unsupported abi type %s", t.String())
+}
+
+// abiInteger converts a JSON number or a numeric string to an
+// integer of type t. Integers that are not 8, 16, 32 or 64 bits
+// wide, e.g. uint24 or int256, are *big.Int.
+func abiInteger(value interface{}, t abi.Type) (interface{}, error) {
+	number := new(big.Int)
+	switch v := value.(type) {
+	case string:
+		if _, ok := number.SetString(v, 0); !ok {
+			return nil, fmt.Errorf("%s is not an integer", v)
+		}
+	case float64:
+		if _, ok := number.SetString(big.NewFloat(v).Text('f', -1), 10); !ok {
+			return nil, fmt.Errorf("%v is not an integer", v)
+		}
+	default:
+		return nil, fmt.Errorf("%v is not an integer", value)
+	}
+
+	if t.T == abi.UintTy && number.Sign() < 0 {
+		return nil, fmt.Errorf("%s is negative", number.String())
+	}
+	if t.T == abi.UintTy && number.BitLen() > t.Size {
+		return nil, fmt.Errorf("%s overflows %s", number.String(), t.String())
+	}
+	// signed integers range from -2^(size-1) to 2^(size-1)-1
+	if t.T == abi.IntTy {
+		max := new(big.Int).Lsh(big.NewInt(1), uint(t.Size-1))
+		min := new(big.Int).Neg(max)
+		if number.Cmp(min) < 0 || number.Cmp(max) >= 0 {
+			return nil, fmt.Errorf("%s overflows %s", number.String(), t.String())
+		}
+	}
+	if t.Kind == reflect.Ptr {
+		return number, nil
+	}
+
+	if t.T == abi.UintTy {
+		return reflect.ValueOf(number.Uint64()).Convert(t.Type).Interface(), nil
+	}
+	return reflect.ValueOf(number.Int64()).Convert(t.Type).Interface(), nil
+}
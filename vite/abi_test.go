@@ -0,0 +1,157 @@
+package vite
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/vitelabs/go-vite/vm/abi"
+)
+
+func bigInt(t *testing.T, value string) *big.Int {
+	number, ok := new(big.Int).SetString(value, 0)
+	if !ok {
+		t.Fatalf("%s is not an integer", value)
+	}
+	return number
+}
+
+func TestAbiInteger(t *testing.T) {
+	tests := []struct {
+		name     string
+		abiType  string
+		value    interface{}
+		expected interface{}
+		err      bool
+	}{
+		{name: "int8 max", abiType: "int8", value: "127", expected: int8(127)},
+		{name: "int8 min", abiType: "int8", value: "-128", expected: int8(-128)},
+		{name: "int8 above max", abiType: "int8", value: "128", err: true},
+		{name: "int8 wraps", abiType: "int8", value: "200", err: true},
+		{name: "int8 below min", abiType: "int8", value: "-129", err: true},
+		{name: "uint8 max", abiType: "uint8", value: "255", expected: uint8(255)},
+		{name: "uint8 above max", abiType: "uint8", value: "256", err: true},
+		{name: "uint8 negative", abiType: "uint8", value: "-1", err: true},
+		{name: "uint24 max", abiType: "uint24", value: "16777215", expected: "16777215"},
+		{name: "uint24 above max", abiType: "uint24", value: "16777216", err: true},
+		{name: "int40 max", abiType: "int40", value: "549755813887", expected: "549755813887"},
+		{name: "int40 min", abiType: "int40", value: "-549755813888", expected: "-549755813888"},
+		{name: "int40 above max", abiType: "int40", value: "549755813888", err: true},
+		{name: "int40 below min", abiType: "int40", value: "-549755813889", err: true},
+		{name: "uint64 hex", abiType: "uint64", value: "0xff", expected: uint64(255)},
+		{name: "int64 json number", abiType: "int64", value: float64(-42), expected: int64(-42)},
+		{name: "fraction", abiType: "int64", value: float64(1.5), err: true},
+		{name: "not a number", abiType: "uint32", value: "abc", err: true},
+		{name: "bool", abiType: "uint32", value: true, err: true},
+		{
+			name:     "int256 max",
+			abiType:  "int256",
+			value:    "57896044618658097711785492504343953926634992332820282019728792003956564819967",
+			expected: "57896044618658097711785492504343953926634992332820282019728792003956564819967",
+		},
+		{
+			name:    "int256 above max",
+			abiType: "int256",
+			value:   "57896044618658097711785492504343953926634992332820282019728792003956564819968",
+			err:     true,
+		},
+		{
+			name:     "int256 min",
+			abiType:  "int256",
+			value:    "-57896044618658097711785492504343953926634992332820282019728792003956564819968",
+			expected: "-57896044618658097711785492504343953926634992332820282019728792003956564819968",
+		},
+		{
+			name:     "uint256 max",
+			abiType:  "uint256",
+			value:    "115792089237316195423570985008687907853269984665640564039457584007913129639935",
+			expected: "115792089237316195423570985008687907853269984665640564039457584007913129639935",
+		},
+		{
+			name:    "uint256 above max",
+			abiType: "uint256",
+			value:   "115792089237316195423570985008687907853269984665640564039457584007913129639936",
+			err:     true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			abiType, err := abi.NewType(test.abiType)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			result, err := abiInteger(test.value, abiType)
+			if test.err {
+				if err == nil {
+					t.Fatalf("expected an error, got %v", result)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expected := test.expected
+			if value, ok := expected.(string); ok {
+				expected = bigInt(t, value)
+			}
+			if !reflect.DeepEqual(result, expected) {
+				t.Fatalf("expected %v (%T), got %v (%T)", expected, expected, result, result)
+			}
+		})
+	}
+}
+
+func TestAbiArgument(t *testing.T) {
+	tests := []struct {
+		name     string
+		abiType  string
+		value    interface{}
+		expected interface{}
+		err      bool
+	}{
+		{name: "bool", abiType: "bool", value: true, expected: true},
+		{name: "bool string", abiType: "bool", value: "false", expected: false},
+		{name: "bool numeric string", abiType: "bool", value: "1", expected: true},
+		{name: "bool typo", abiType: "bool", value: "flase", err: true},
+		{name: "bool yes", abiType: "bool", value: "yes", err: true},
+		{name: "bool number", abiType: "bool", value: float64(1), err: true},
+		{name: "string", abiType: "string", value: "vite", expected: "vite"},
+		{name: "string number", abiType: "string", value: float64(1), err: true},
+		{name: "bytes", abiType: "bytes", value: "0x0102", expected: []byte{1, 2}},
+		{name: "bytes invalid", abiType: "bytes", value: "0xzz", err: true},
+		{name: "bytes2", abiType: "bytes2", value: "0x01", expected: [2]byte{1, 0}},
+		{name: "bytes2 too long", abiType: "bytes2", value: "0x010203", err: true},
+		{name: "address invalid", abiType: "address", value: "vite_00", err: true},
+		{name: "uint8 slice", abiType: "uint8[]", value: []interface{}{float64(1), float64(2)}, expected: []uint8{1, 2}},
+		{name: "uint8 slice string", abiType: "uint8[]", value: "[3]", expected: []uint8{3}},
+		{name: "int8 overflow", abiType: "int8", value: "200", err: true},
+		{name: "uint24", abiType: "uint24", value: float64(7), expected: big.NewInt(7)},
+		{name: "int40 slice", abiType: "int40[]", value: []interface{}{float64(-1)}, expected: []*big.Int{big.NewInt(-1)}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			abiType, err := abi.NewType(test.abiType)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			result, err := abiArgument(test.value, abiType)
+			if test.err {
+				if err == nil {
+					t.Fatalf("expected an error, got %v", result)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(result, test.expected) {
+				t.Fatalf("expected %v (%T), got %v (%T)", test.expected, test.expected, result, result)
+			}
+		})
+	}
+}
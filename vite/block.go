@@ -0,0 +1,228 @@
+package vite
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/azbuky/rosetta-vite/vite/rpc"
+
+	viteTypes "github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/ledger"
+	"github.com/vitelabs/go-vite/rpcapi/api"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// DefaultBlockConcurrency is the default maximum number of
+	// account chains fetched concurrently when populating a block.
+	DefaultBlockConcurrency = 8
+
+	// DefaultBlockBatchSize is the default maximum number of
+	// account blocks requested in a single range query.
+	DefaultBlockBatchSize = uint64(64)
+
+	// initialBlockBatchSize is the number of account blocks requested
+	// in the first range query of an account chain. Most accounts have
+	// few blocks in a snapshot block, the batch size is doubled for
+	// every following query up to the configured batch size.
+	initialBlockBatchSize = uint64(2)
+
+	// blockAttempts is the number of times a block is assembled
+	// when the snapshot chain rolls back in the meantime.
+	blockAttempts = 3
+)
+
+// snapshotAccountBlocks returns all account blocks confirmed for the
+// first time by a snapshot block. The first range query of every
+// account chain is sent in a single batch request, longer chains are
+// then fetched concurrently. The result is ordered by address and by
+// descending height within an account chain.
+func (ec *Client) snapshotAccountBlocks(
+	ctx context.Context,
+	block *api.SnapshotBlock,
+) ([]*api.AccountBlock, error) {
+	addresses := make([]viteTypes.Address, 0, len(block.SnapshotData))
+	for address := range block.SnapshotData {
+		addresses = append(addresses, address)
+	}
+	sort.Slice(addresses, func(i, j int) bool {
+		return bytes.Compare(addresses[i].Bytes(), addresses[j].Bytes()) < 0
+	})
+
+	batchSize := ec.blockBatchSize
+	if batchSize == 0 {
+		batchSize = DefaultBlockBatchSize
+	}
+
+	chains := make([]*accountChain, len(addresses))
+	pending := []*accountChain{}
+	calls := []*rpc.BatchCall{}
+	results := []*[]*api.AccountBlock{}
+	for i, address := range addresses {
+		chains[i] = newAccountChain(address, block.SnapshotData[address], block.Hash, batchSize)
+		if chains[i].done {
+			continue
+		}
+		call, result := chains[i].request()
+		pending = append(pending, chains[i])
+		calls = append(calls, call)
+		results = append(results, result)
+	}
+	if err := ec.c.BatchCallContext(ctx, calls); err != nil {
+		return nil, err
+	}
+	for i, chain := range pending {
+		if calls[i].Error != nil {
+			return nil, calls[i].Error
+		}
+		if err := chain.add(*results[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	concurrency := ec.blockConcurrency
+	if concurrency <= 0 {
+		concurrency = DefaultBlockConcurrency
+	}
+
+	semaphore := make(chan struct{}, concurrency)
+	g, ctx := errgroup.WithContext(ctx)
+	for _, chain := range pending {
+		if chain.done {
+			continue
+		}
+		chain := chain
+
+		select {
+		case semaphore <- struct{}{}:
+		case <-ctx.Done():
+			if err := g.Wait(); err != nil {
+				return nil, err
+			}
+			return nil, ctx.Err()
+		}
+
+		g.Go(func() error {
+			defer func() { <-semaphore }()
+
+			for !chain.done {
+				chain.limitCount()
+				blocks, err := ec.c.GetAccountBlocks(ctx, chain.address, &chain.hash, chain.count)
+				if err != nil {
+					return err
+				}
+				if err := chain.add(blocks); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	accountBlocks := []*api.AccountBlock{}
+	for _, chain := range chains {
+		accountBlocks = append(accountBlocks, chain.blocks...)
+	}
+
+	return accountBlocks, nil
+}
+
+// accountChain collects the account blocks of an address confirmed for
+// the first time by a snapshot block, walking back the account chain
+// with range queries of increasing size.
+type accountChain struct {
+	address      viteTypes.Address
+	snapshotHash viteTypes.Hash
+	batchSize    uint64
+
+	// hash is the first block of the next range query
+	hash      viteTypes.Hash
+	remaining uint64
+	count     uint64
+
+	blocks []*api.AccountBlock
+	done   bool
+}
+
+func newAccountChain(
+	address viteTypes.Address,
+	hashHeight *ledger.HashHeight,
+	snapshotHash viteTypes.Hash,
+	batchSize uint64,
+) *accountChain {
+	return &accountChain{
+		address:      address,
+		snapshotHash: snapshotHash,
+		batchSize:    batchSize,
+		hash:         hashHeight.Hash,
+		remaining:    hashHeight.Height,
+		count:        initialBlockBatchSize,
+		blocks:       []*api.AccountBlock{},
+		done:         hashHeight.Height == 0,
+	}
+}
+
+// request returns the next range query of the account chain
+// as a batch call.
+func (c *accountChain) request() (*rpc.BatchCall, *[]*api.AccountBlock) {
+	c.limitCount()
+	hash := c.hash
+	return rpc.NewGetAccountBlocksCall(c.address, &hash, c.count)
+}
+
+// limitCount caps the size of the next range query to the batch
+// size and to the number of blocks left in the account chain.
+func (c *accountChain) limitCount() {
+	if c.count > c.batchSize {
+		c.count = c.batchSize
+	}
+	if c.count > c.remaining {
+		c.count = c.remaining
+	}
+}
+
+// add appends the result of the last range query, the account chain
+// is done once a block confirmed by another snapshot block is found.
+// A missing head or an unconfirmed block means the account chain no
+// longer matches the snapshot block, which was rolled back.
+func (c *accountChain) add(blocks []*api.AccountBlock) error {
+	if len(c.blocks) == 0 && (len(blocks) == 0 || blocks[0].Hash != c.hash) {
+		return fmt.Errorf(
+			"%w: account block %s of %s not found",
+			ErrSnapshotRolledBack,
+			c.hash.Hex(),
+			c.address.String(),
+		)
+	}
+
+	for _, account := range blocks {
+		if account.FirstSnapshotHash == nil {
+			return fmt.Errorf(
+				"%w: account block %s of %s is not confirmed",
+				ErrSnapshotRolledBack,
+				account.Hash.Hex(),
+				c.address.String(),
+			)
+		}
+		if *account.FirstSnapshotHash != c.snapshotHash {
+			c.done = true
+			return nil
+		}
+		c.blocks = append(c.blocks, account)
+	}
+
+	if uint64(len(blocks)) < c.count || c.remaining <= c.count {
+		c.done = true
+		return nil
+	}
+
+	c.hash = blocks[len(blocks)-1].PreviousHash
+	c.remaining -= c.count
+	c.count *= 2
+	return nil
+}
@@ -0,0 +1,223 @@
+package vite
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+
+	viteTypes "github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/rpcapi/api"
+)
+
+const (
+	// DefaultBlockCacheSize is the default maximum number
+	// of snapshot blocks kept in the block cache.
+	DefaultBlockCacheSize = 1000
+
+	// DefaultConfirmationDepth is the default number of snapshot
+	// blocks after which a snapshot block is considered final.
+	DefaultConfirmationDepth = uint64(100)
+)
+
+// cachedBlock is a snapshot block and, once it was populated,
+// the rendered Rosetta block.
+type cachedBlock struct {
+	snapshot *api.SnapshotBlock
+
+	block *types.Block
+	txIds []*types.TransactionIdentifier
+
+	element *list.Element
+}
+
+// isFinal returns true if the block was final when cached.
+func (e *cachedBlock) isFinal() bool {
+	return e.element != nil
+}
+
+// blockCache caches snapshot blocks and rendered Rosetta blocks by
+// hash and by height. Blocks at least confirmationDepth below the tip
+// are final, they are kept until they are evicted by the size limit.
+// Blocks near the tip are kept only until the tip moves, since they
+// may still be replaced by a fork of the snapshot chain.
+type blockCache struct {
+	size              int
+	confirmationDepth uint64
+
+	mu  sync.Mutex
+	tip uint64
+
+	// final blocks, evicted in least recently used order
+	final  map[viteTypes.Hash]*cachedBlock
+	lru    *list.List
+	recent map[viteTypes.Hash]*cachedBlock
+	height map[uint64]viteTypes.Hash
+}
+
+// newBlockCache creates a blockCache, a nil blockCache
+// is returned if size is 0 to disable caching.
+func newBlockCache(size int, confirmationDepth uint64) *blockCache {
+	if size <= 0 {
+		return nil
+	}
+
+	return &blockCache{
+		size:              size,
+		confirmationDepth: confirmationDepth,
+		final:             map[viteTypes.Hash]*cachedBlock{},
+		lru:               list.New(),
+		recent:            map[viteTypes.Hash]*cachedBlock{},
+		height:            map[uint64]viteTypes.Hash{},
+	}
+}
+
+// setTip records the height of the latest snapshot block. When the tip
+// moves all blocks that were not final when cached are evicted.
+func (c *blockCache) setTip(height uint64) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if height == c.tip {
+		return
+	}
+	c.tip = height
+	c.clearRecent()
+}
+
+// invalidate evicts all blocks that were not final when
+// cached, it is called when blocks are rolled back.
+func (c *blockCache) invalidate() {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.clearRecent()
+}
+
+// clearRecent evicts all blocks that were not final when cached.
+func (c *blockCache) clearRecent() {
+	for hash, entry := range c.recent {
+		if c.height[entry.snapshot.Height] == hash {
+			delete(c.height, entry.snapshot.Height)
+		}
+	}
+	c.recent = map[viteTypes.Hash]*cachedBlock{}
+}
+
+// byHash returns the cached entry of a snapshot block hash.
+func (c *blockCache) byHash(hash viteTypes.Hash) *cachedBlock {
+	if c == nil {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.copy(c.get(hash))
+}
+
+// byHeight returns the cached entry of a snapshot block height.
+func (c *blockCache) byHeight(height uint64) *cachedBlock {
+	if c == nil {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	hash, ok := c.height[height]
+	if !ok {
+		return nil
+	}
+	return c.copy(c.get(hash))
+}
+
+// copy returns a copy of an entry, since
+// entries are updated while holding the lock.
+func (c *blockCache) copy(entry *cachedBlock) *cachedBlock {
+	if entry == nil {
+		return nil
+	}
+	result := *entry
+	return &result
+}
+
+func (c *blockCache) get(hash viteTypes.Hash) *cachedBlock {
+	if entry, ok := c.final[hash]; ok {
+		c.lru.MoveToFront(entry.element)
+		return entry
+	}
+	return c.recent[hash]
+}
+
+// addSnapshot caches a snapshot block.
+func (c *blockCache) addSnapshot(snapshot *api.SnapshotBlock) {
+	if c == nil || snapshot == nil || snapshot.Hash.IsZero() {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.get(snapshot.Hash) != nil {
+		return
+	}
+	c.add(&cachedBlock{snapshot: snapshot})
+}
+
+// addBlock caches the rendered Rosetta block of a snapshot block.
+func (c *blockCache) addBlock(
+	snapshot *api.SnapshotBlock,
+	block *types.Block,
+	txIds []*types.TransactionIdentifier,
+) {
+	if c == nil || snapshot == nil || snapshot.Hash.IsZero() {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := c.get(snapshot.Hash)
+	if entry == nil {
+		entry = &cachedBlock{snapshot: snapshot}
+		c.add(entry)
+	}
+	entry.block = block
+	entry.txIds = txIds
+}
+
+func (c *blockCache) add(entry *cachedBlock) {
+	height := entry.snapshot.Height
+
+	if height+c.confirmationDepth > c.tip {
+		if len(c.recent) >= c.size {
+			c.clearRecent()
+		}
+		c.height[height] = entry.snapshot.Hash
+		c.recent[entry.snapshot.Hash] = entry
+		return
+	}
+
+	c.height[height] = entry.snapshot.Hash
+	entry.element = c.lru.PushFront(entry.snapshot.Hash)
+	c.final[entry.snapshot.Hash] = entry
+
+	for c.lru.Len() > c.size {
+		oldest := c.lru.Back()
+		hash := c.lru.Remove(oldest).(viteTypes.Hash)
+		evicted := c.final[hash]
+		delete(c.final, hash)
+		if c.height[evicted.snapshot.Height] == hash {
+			delete(c.height, evicted.snapshot.Height)
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package vite
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+
+	viteTypes "github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/ledger"
+	"github.com/vitelabs/go-vite/rpcapi/api"
+)
+
+func testSnapshotBlock(height uint64, fork byte) *api.SnapshotBlock {
+	var hash viteTypes.Hash
+	binary.BigEndian.PutUint64(hash[:], height)
+	hash[len(hash)-1] = fork + 1
+
+	return &api.SnapshotBlock{
+		SnapshotBlock: &ledger.SnapshotBlock{
+			Hash:   hash,
+			Height: height,
+		},
+	}
+}
+
+func TestBlockCacheFinality(t *testing.T) {
+	tests := []struct {
+		name   string
+		tip    uint64
+		height uint64
+		final  bool
+	}{
+		{name: "tip", tip: 100, height: 100, final: false},
+		{name: "below depth", tip: 100, height: 91, final: false},
+		{name: "at depth", tip: 100, height: 90, final: true},
+		{name: "above depth", tip: 100, height: 1, final: true},
+		{name: "above tip", tip: 100, height: 101, final: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cache := newBlockCache(10, 10)
+			cache.setTip(test.tip)
+
+			snapshot := testSnapshotBlock(test.height, 0)
+			cache.addSnapshot(snapshot)
+
+			entry := cache.byHeight(test.height)
+			if entry == nil {
+				t.Fatal("block is not cached")
+			}
+			if entry.isFinal() != test.final {
+				t.Fatalf("expected final %t, got %t", test.final, entry.isFinal())
+			}
+
+			// only final blocks survive a tip change or a rollback
+			cache.setTip(test.tip + 1)
+			if (cache.byHash(snapshot.Hash) != nil) != test.final {
+				t.Fatalf("expected cached after setTip %t", test.final)
+			}
+			cache.invalidate()
+			if (cache.byHeight(test.height) != nil) != test.final {
+				t.Fatalf("expected cached after invalidate %t", test.final)
+			}
+		})
+	}
+}
+
+func TestBlockCacheSetTip(t *testing.T) {
+	cache := newBlockCache(10, 10)
+	cache.setTip(100)
+
+	snapshot := testSnapshotBlock(95, 0)
+	cache.addSnapshot(snapshot)
+
+	// an unchanged tip keeps recent blocks
+	cache.setTip(100)
+	if cache.byHash(snapshot.Hash) == nil {
+		t.Fatal("recent block evicted without a tip change")
+	}
+
+	cache.setTip(101)
+	if cache.byHash(snapshot.Hash) != nil {
+		t.Fatal("recent block not evicted by a tip change")
+	}
+	if cache.byHeight(snapshot.Height) != nil {
+		t.Fatal("recent block height not evicted by a tip change")
+	}
+
+	// blocks cached again once the tip moved past
+	// the confirmation depth are promoted to final
+	cache.setTip(105)
+	cache.addSnapshot(snapshot)
+	entry := cache.byHash(snapshot.Hash)
+	if entry == nil || !entry.isFinal() {
+		t.Fatal("block not cached as final")
+	}
+	cache.setTip(106)
+	if cache.byHash(snapshot.Hash) == nil {
+		t.Fatal("final block evicted by a tip change")
+	}
+}
+
+func TestBlockCacheInvalidateFork(t *testing.T) {
+	cache := newBlockCache(10, 10)
+	cache.setTip(100)
+
+	final := testSnapshotBlock(80, 0)
+	orphaned := testSnapshotBlock(100, 0)
+	cache.addSnapshot(final)
+	cache.addBlock(orphaned, &types.Block{}, nil)
+
+	cache.invalidate()
+	if cache.byHash(orphaned.Hash) != nil {
+		t.Fatal("orphaned block not evicted")
+	}
+	if cache.byHash(final.Hash) == nil {
+		t.Fatal("final block evicted")
+	}
+
+	// the replacing block is cached at the same height
+	replacement := testSnapshotBlock(100, 1)
+	cache.addSnapshot(replacement)
+	entry := cache.byHeight(100)
+	if entry == nil || entry.snapshot.Hash != replacement.Hash {
+		t.Fatal("replacing block not cached by height")
+	}
+}
+
+func TestBlockCacheEviction(t *testing.T) {
+	cache := newBlockCache(2, 10)
+	cache.setTip(100)
+
+	first := testSnapshotBlock(1, 0)
+	second := testSnapshotBlock(2, 0)
+	third := testSnapshotBlock(3, 0)
+	cache.addSnapshot(first)
+	cache.addSnapshot(second)
+
+	// touch the first block so the second is least recently used
+	if cache.byHash(first.Hash) == nil {
+		t.Fatal("first block is not cached")
+	}
+	cache.addSnapshot(third)
+
+	if cache.byHash(second.Hash) != nil || cache.byHeight(second.Height) != nil {
+		t.Fatal("least recently used block not evicted")
+	}
+	if cache.byHash(first.Hash) == nil || cache.byHash(third.Hash) == nil {
+		t.Fatal("recently used blocks evicted")
+	}
+}
+
+func TestBlockCacheAddBlock(t *testing.T) {
+	cache := newBlockCache(10, 10)
+	cache.setTip(100)
+
+	snapshot := testSnapshotBlock(50, 0)
+	cache.addSnapshot(snapshot)
+	if entry := cache.byHash(snapshot.Hash); entry == nil || entry.block != nil {
+		t.Fatal("snapshot not cached without a block")
+	}
+
+	block := &types.Block{BlockIdentifier: &types.BlockIdentifier{Index: 50}}
+	txIds := []*types.TransactionIdentifier{{Hash: "tx"}}
+	cache.addBlock(snapshot, block, txIds)
+
+	entry := cache.byHeight(50)
+	if entry == nil || entry.block != block || len(entry.txIds) != 1 {
+		t.Fatal("rendered block not cached")
+	}
+}
+
+func TestBlockCacheDisabled(t *testing.T) {
+	cache := newBlockCache(0, 10)
+	if cache != nil {
+		t.Fatal("expected a nil cache")
+	}
+
+	snapshot := testSnapshotBlock(1, 0)
+	cache.setTip(100)
+	cache.addSnapshot(snapshot)
+	cache.addBlock(snapshot, &types.Block{}, nil)
+	cache.invalidate()
+	if cache.byHash(snapshot.Hash) != nil || cache.byHeight(1) != nil {
+		t.Fatal("disabled cache returned a block")
+	}
+}
@@ -0,0 +1,234 @@
+package vite
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+
+	viteTypes "github.com/vitelabs/go-vite/common/types"
+)
+
+// callParamKind is the kind of value expected
+// for a call method parameter.
+type callParamKind string
+
+const (
+	addressParam callParamKind = "address"
+	tokenIdParam callParamKind = "tokenId"
+	hashParam    callParamKind = "hash"
+	uintParam    callParamKind = "uint"
+	stringParam  callParamKind = "string"
+)
+
+// callParam describes a positional parameter of a gvite method.
+type callParam struct {
+	Name     string
+	Kind     callParamKind
+	Required bool
+}
+
+// callMethod describes the parameters accepted by a gvite method
+// exposed through /call.
+type callMethod struct {
+	Params     []callParam
+	Idempotent bool
+}
+
+// callMethods contains the schema of every gvite method that
+// can be allowed in /call.
+var callMethods = map[string]callMethod{
+	"contract_getTokenInfoList": {
+		Params: []callParam{
+			{Name: "pageIndex", Kind: uintParam, Required: true},
+			{Name: "pageSize", Kind: uintParam, Required: true},
+		},
+	},
+	"contract_getTokenInfoById": {
+		Params: []callParam{
+			{Name: "tokenId", Kind: tokenIdParam, Required: true},
+		},
+	},
+	"contract_getTokenInfoListByOwner": {
+		Params: []callParam{
+			{Name: "owner", Kind: addressParam, Required: true},
+		},
+	},
+	"contract_getStakeList": {
+		Params: []callParam{
+			{Name: "address", Kind: addressParam, Required: true},
+			{Name: "pageIndex", Kind: uintParam, Required: true},
+			{Name: "pageSize", Kind: uintParam, Required: true},
+		},
+	},
+	"contract_getQuotaByAccount": {
+		Params: []callParam{
+			{Name: "address", Kind: addressParam, Required: true},
+		},
+	},
+	"contract_getContractInfo": {
+		Params: []callParam{
+			{Name: "address", Kind: addressParam, Required: true},
+		},
+	},
+	"ledger_getVmLogs": {
+		Params: []callParam{
+			{Name: "blockHash", Kind: hashParam, Required: true},
+		},
+		Idempotent: true,
+	},
+	"ledger_getAccountInfoByAddress": {
+		Params: []callParam{
+			{Name: "address", Kind: addressParam, Required: true},
+		},
+	},
+	"ledger_getUnreceivedTransactionSummaryByAddress": {
+		Params: []callParam{
+			{Name: "address", Kind: addressParam, Required: true},
+		},
+	},
+	"ledger_getAccountBlockByHeight": {
+		Params: []callParam{
+			{Name: "address", Kind: addressParam, Required: true},
+			{Name: "height", Kind: uintParam, Required: true},
+		},
+		Idempotent: true,
+	},
+}
+
+func init() {
+	for method := range callMethods {
+		CallMethods = append(CallMethods, method)
+	}
+	sort.Strings(CallMethods)
+}
+
+// ValidateCallMethods ensures that every method in
+// the list can be exposed through /call.
+func ValidateCallMethods(methods []string) error {
+	for _, method := range methods {
+		if _, ok := callMethods[method]; !ok {
+			return fmt.Errorf("%w: %s", ErrCallMethodInvalid, method)
+		}
+	}
+	return nil
+}
+
+// Call forwards an allowed method to gvite and returns its result.
+// Results that are not JSON objects are returned under the
+// "result" key.
+func (ec *Client) Call(
+	ctx context.Context,
+	method string,
+	parameters map[string]interface{},
+) (map[string]interface{}, bool, error) {
+	if !ec.callAllowed(method) {
+		return nil, false, fmt.Errorf("%w: %s", ErrCallMethodInvalid, method)
+	}
+
+	schema := callMethods[method]
+	args, err := callArguments(schema, parameters)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var raw json.RawMessage
+	if err := ec.c.CallContext(ctx, &raw, method, args...); err != nil {
+		return nil, false, err
+	}
+
+	var result interface{}
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, false, fmt.Errorf("%w: %s", ErrCallOutputMarshal, err.Error())
+	}
+
+	if resultMap, ok := result.(map[string]interface{}); ok {
+		return resultMap, schema.Idempotent, nil
+	}
+
+	return map[string]interface{}{
+		"result": result,
+	}, schema.Idempotent, nil
+}
+
+// callAllowed checks if a method is in the configured allowlist.
+func (ec *Client) callAllowed(method string) bool {
+	for _, allowed := range ec.callMethods {
+		if allowed == method {
+			return true
+		}
+	}
+	return false
+}
+
+// callArguments validates the named parameters against the method
+// schema and returns them as positional arguments.
+func callArguments(
+	schema callMethod,
+	parameters map[string]interface{},
+) ([]interface{}, error) {
+	for name := range parameters {
+		known := false
+		for _, param := range schema.Params {
+			if param.Name == name {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return nil, fmt.Errorf("%w: unknown parameter %s", ErrCallParametersInvalid, name)
+		}
+	}
+
+	args := []interface{}{}
+	for _, param := range schema.Params {
+		value, ok := parameters[param.Name]
+		if !ok || value == nil {
+			if param.Required {
+				return nil, fmt.Errorf("%w: missing parameter %s", ErrCallParametersInvalid, param.Name)
+			}
+			args = append(args, nil)
+			continue
+		}
+
+		arg, err := callArgument(param, value)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+	}
+
+	return args, nil
+}
+
+// callArgument validates a single parameter value.
+func callArgument(param callParam, value interface{}) (interface{}, error) {
+	if param.Kind == uintParam {
+		number, ok := value.(float64)
+		if !ok || number < 0 || number != math.Trunc(number) {
+			return nil, fmt.Errorf("%w: %s must be a non-negative integer", ErrCallParametersInvalid, param.Name)
+		}
+		return uint64(number), nil
+	}
+
+	str, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s must be a string", ErrCallParametersInvalid, param.Name)
+	}
+
+	var err error
+	switch param.Kind {
+	case addressParam:
+		_, err = viteTypes.HexToAddress(str)
+	case tokenIdParam:
+		_, err = viteTypes.HexToTokenTypeId(str)
+	case hashParam:
+		_, err = viteTypes.HexToHash(str)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s is not a valid %s", ErrCallParametersInvalid, param.Name, param.Kind)
+	}
+
+	return str, nil
+}
@@ -2,14 +2,19 @@ package vite
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/big"
 	"strconv"
+	"sync"
+	"time"
 
+	"github.com/azbuky/rosetta-vite/metrics"
 	"github.com/azbuky/rosetta-vite/vite/rpc"
 	"github.com/coinbase/rosetta-sdk-go/types"
 
 	viteTypes "github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/ledger"
 	"github.com/vitelabs/go-vite/net"
 	"github.com/vitelabs/go-vite/rpcapi/api"
 )
@@ -17,29 +22,99 @@ import (
 type Client struct {
 	c rpc.RpcClient
 
-	inlineTransactions bool
+	network    string
+	supervisor *GviteSupervisor
+	subscriber *Subscriber
 
+	inlineTransactions bool
+	mempoolAddresses   []viteTypes.Address
+	callMethods        []string
+	powSolver          PoWSolver
+	powThreads         int
+	localPoWTimeout    time.Duration // DefaultLocalPoWTimeout if 0
+	blockConcurrency   int
+	blockBatchSize     uint64
+	tokens             *TokenRegistry
+	cache              *blockCache
+
+	// genesisBlockIdentifier is retrieved on first use,
+	// so that gvite does not need to be ready at startup
+	genesisMu              sync.Mutex
 	genesisBlockIdentifier *types.BlockIdentifier
 }
 
+// ClientOptions defines the optional settings
+// used when creating a Client.
+type ClientOptions struct {
+	// Network is the name of the network reported in metrics
+	Network string
+
+	// Supervisor runs the local gvite node, nil if gvite is remote
+	Supervisor *GviteSupervisor
+
+	// SubscriptionURL is the gvite WebSocket URL used to subscribe
+	// to new blocks, subscriptions are disabled if it is empty
+	SubscriptionURL string
+
+	// InlineTransactions determines if transactions are
+	// returned inline in /block or as other_transactions
+	InlineTransactions bool
+
+	// MempoolAddresses are the addresses whose unreceived
+	// blocks are reported in /mempool
+	MempoolAddresses []viteTypes.Address
+
+	// CallMethods are the gvite methods allowed in /call
+	CallMethods []string
+
+	// PoWSolver selects how PoW nonces are computed
+	PoWSolver PoWSolver
+
+	// PoWThreads is the number of goroutines used by
+	// the local PoW solver, 0 uses all CPUs
+	PoWThreads int
+
+	// BlockConcurrency is the maximum number of account chains
+	// fetched concurrently when populating a block
+	BlockConcurrency int
+
+	// BlockBatchSize is the number of account blocks
+	// requested in a single range query
+	BlockBatchSize uint64
+
+	// BlockCacheSize is the maximum number of snapshot
+	// blocks cached, 0 disables the block cache
+	BlockCacheSize int
+
+	// ConfirmationDepth is the number of snapshot blocks
+	// after which a cached block is kept permanently
+	ConfirmationDepth uint64
+}
+
 // NewClient creates a Client that from the provided url and params.
-func NewClient(url string, inlineTransactions bool) (*Client, error) {
+func NewClient(url string, options *ClientOptions) (*Client, error) {
 	c, err := rpc.NewRpcClient(url)
 	if err != nil {
 		return nil, fmt.Errorf("%w: unable to dial node", err)
 	}
 
-	genesisBlock, err := c.GetSnapshotGenesisBlock()
-	if err != nil {
-		return nil, fmt.Errorf("%w: unable to get genesis block", err)
+	client := &Client{
+		c:                  c,
+		network:            options.Network,
+		supervisor:         options.Supervisor,
+		inlineTransactions: options.InlineTransactions,
+		mempoolAddresses:   options.MempoolAddresses,
+		callMethods:        options.CallMethods,
+		powSolver:          options.PoWSolver,
+		powThreads:         options.PoWThreads,
+		blockConcurrency:   options.BlockConcurrency,
+		blockBatchSize:     options.BlockBatchSize,
+		tokens:             NewTokenRegistry(c),
+		cache:              newBlockCache(options.BlockCacheSize, options.ConfirmationDepth),
 	}
+	client.subscriber = newSubscriber(client, options.SubscriptionURL)
 
-	genesisBlockIdentifier := &types.BlockIdentifier{
-		Hash:  genesisBlock.Hash.Hex(),
-		Index: int64(genesisBlock.Height),
-	}
-
-	return &Client{c, inlineTransactions, genesisBlockIdentifier}, nil
+	return client, nil
 }
 
 // Close shuts down the RPC client connection.
@@ -47,13 +122,56 @@ func (ec *Client) Close() {
 	ec.c.GetClient().Close()
 }
 
-// GenesisBlockIdentifier returns cached genesis block identifier
-func (ec *Client) GenesisBlockIdentifier() *types.BlockIdentifier {
-	return ec.genesisBlockIdentifier
+// StartTokenRegistry loads the token list and keeps
+// it up to date until the context is canceled.
+func (ec *Client) StartTokenRegistry(ctx context.Context) error {
+	return ec.tokens.Start(ctx)
+}
+
+// GviteReady returns an error if the local gvite
+// node is not running, a remote node is always ready.
+func (ec *Client) GviteReady() error {
+	if ec.supervisor == nil || ec.supervisor.Running() {
+		return nil
+	}
+
+	return gviteNotRunningError(ec.supervisor.Status())
+}
+
+// GenesisBlockIdentifier returns the genesis block identifier,
+// it is retrieved from gvite once and cached.
+func (ec *Client) GenesisBlockIdentifier(ctx context.Context) (*types.BlockIdentifier, error) {
+	ec.genesisMu.Lock()
+	defer ec.genesisMu.Unlock()
+
+	if ec.genesisBlockIdentifier != nil {
+		return ec.genesisBlockIdentifier, nil
+	}
+
+	genesisBlock, err := ec.c.GetSnapshotBlockByHeight(ctx, uint64(GenesisBlockIndex))
+	if err != nil {
+		return nil, fmt.Errorf("%w: unable to get genesis block", err)
+	}
+	if genesisBlock == nil {
+		return nil, fmt.Errorf("genesis block not found")
+	}
+
+	ec.genesisBlockIdentifier = ec.getBlockIdentifier(genesisBlock)
+	return ec.genesisBlockIdentifier, nil
+}
+
+// nodeStatus is the gvite status information returned by Status.
+type nodeStatus struct {
+	block      *types.BlockIdentifier
+	timestamp  int64
+	syncStatus *types.SyncStatus
+	peers      []*types.Peer
 }
 
-// Status returns gvite status information
-// for determining node healthiness.
+// Status returns gvite status information for determining
+// node healthiness. While a snapshot block subscription is
+// live the status tracked by the subscriber is returned,
+// otherwise gvite is polled.
 func (ec *Client) Status(ctx context.Context) (
 	*types.BlockIdentifier,
 	int64,
@@ -61,51 +179,70 @@ func (ec *Client) Status(ctx context.Context) (
 	[]*types.Peer,
 	error,
 ) {
-	nodeInfo, err := ec.c.GetNodeInfo(ctx)
-	if err != nil {
-		return nil, -1, nil, nil, err
+	status := ec.subscriber.status()
+	if status == nil {
+		var err error
+		status, err = ec.fetchStatus(ctx)
+		if err != nil {
+			return nil, -1, nil, nil, err
+		}
 	}
 
-	block, err := ec.c.GetSnapshotBlockByHeight(ctx, nodeInfo.Height)
+	return status.block,
+		status.timestamp,
+		status.syncStatus,
+		status.peers,
+		nil
+}
+
+// fetchStatus polls the gvite status information.
+func (ec *Client) fetchStatus(ctx context.Context) (*nodeStatus, error) {
+	nodeInfo, err := ec.c.GetNodeInfo(ctx)
 	if err != nil {
-		return nil, -1, nil, nil, err
+		return nil, err
 	}
 
-	syncInfo, err := ec.c.GetSyncInfo(ctx)
-
-	if err != nil {
-		return nil, -1, nil, nil, err
+	blockCall, block := rpc.NewGetSnapshotBlockByHeightCall(nodeInfo.Height)
+	syncInfoCall, syncInfo := rpc.NewGetSyncInfoCall()
+	if err := ec.c.BatchCallContext(ctx, []*rpc.BatchCall{blockCall, syncInfoCall}); err != nil {
+		return nil, err
+	}
+	if blockCall.Error != nil {
+		return nil, blockCall.Error
 	}
+	if syncInfoCall.Error != nil {
+		return nil, syncInfoCall.Error
+	}
+	ec.cache.setTip(block.Height)
 
 	var syncStatus *types.SyncStatus
 	if syncInfo != nil {
 		currentIndex, err := strconv.ParseInt(syncInfo.Current, 10, 64)
 		if err != nil {
-			return nil, -1, nil, nil, err
+			return nil, err
 		}
 		stage := fmt.Sprint(syncInfo.State)
-		synced := syncInfo.State == 2
+		synced := syncInfo.State == syncDoneState
 
 		syncStatus = &types.SyncStatus{
 			CurrentIndex: &currentIndex,
 			Stage:        &stage,
 			Synced:       &synced,
 		}
+		metrics.SetNetworkStatus(ec.network, int64(block.Height), syncInfo.State, synced)
 	}
 
 	peers, err := ec.peers(nodeInfo)
 	if err != nil {
-		return nil, -1, nil, nil, err
+		return nil, err
 	}
 
-	blockIdentifier := ec.getBlockIdentifier(block)
-	timestamp := ConvertSecondsToMiliseconds(block.Timestamp)
-
-	return blockIdentifier,
-		timestamp,
-		syncStatus,
-		peers,
-		nil
+	return &nodeStatus{
+		block:      ec.getBlockIdentifier(block),
+		timestamp:  ConvertSecondsToMiliseconds(block.Timestamp),
+		syncStatus: syncStatus,
+		peers:      peers,
+	}, nil
 }
 
 // Get Peers of the node from NodeInfo.
@@ -149,6 +286,9 @@ func (ec *Client) BlockTransaction(
 	if err != nil {
 		return nil, err
 	}
+	if err := ec.tokens.annotate(ctx, accountBlock); err != nil {
+		return nil, err
+	}
 
 	return AccountBlockToTransaction(accountBlock, true)
 }
@@ -156,9 +296,45 @@ func (ec *Client) BlockTransaction(
 // Block returns a populated block at the *RosettaTypes.PartialBlockIdentifier.
 // If neither the hash or index is populated in the blockIdentifier,
 // the current block is returned.
+//
+// All lookups of a block are pinned to the hash of its snapshot block.
+// If the snapshot chain rolls back while the block is assembled, a block
+// requested by index or the current block is assembled again from the
+// new snapshot chain, up to blockAttempts times, before an error wrapping
+// ErrSnapshotRolledBack is returned. A block requested by hash is no
+// longer part of the snapshot chain, an error wrapping ErrBlockOrphaned
+// is returned instead.
 func (ec *Client) Block(
 	ctx context.Context,
 	blockIdentifier *types.PartialBlockIdentifier,
+) (*types.Block, []*types.TransactionIdentifier, error) {
+	pinned := blockIdentifier != nil && blockIdentifier.Hash != nil
+
+	var err error
+	for attempt := 1; attempt <= blockAttempts; attempt++ {
+		var block *types.Block
+		var txIds []*types.TransactionIdentifier
+		block, txIds, err = ec.assembleBlock(ctx, blockIdentifier)
+		if !errors.Is(err, ErrSnapshotRolledBack) {
+			return block, txIds, err
+		}
+
+		// blocks cached before the rollback may belong to the old fork
+		ec.cache.invalidate()
+		if pinned {
+			return nil, nil, fmt.Errorf("%w: %s", ErrBlockOrphaned, err.Error())
+		}
+	}
+
+	return nil, nil, err
+}
+
+// assembleBlock populates the snapshot block at the block identifier
+// and checks that the snapshot block is still part of the snapshot
+// chain once all its account blocks were retrieved.
+func (ec *Client) assembleBlock(
+	ctx context.Context,
+	blockIdentifier *types.PartialBlockIdentifier,
 ) (*types.Block, []*types.TransactionIdentifier, error) {
 	// get SnapshotBlock for block identifier
 	block, err := ec.getSnapshotBlock(ctx, blockIdentifier)
@@ -166,6 +342,56 @@ func (ec *Client) Block(
 		return nil, nil, err
 	}
 
+	if entry := ec.cache.byHash(block.Hash); entry != nil && entry.block != nil {
+		// a fork of the same height does not move the tip, blocks
+		// near the tip are checked before they are served
+		if !entry.isFinal() {
+			if err := ec.checkCanonical(ctx, block); err != nil {
+				return nil, nil, err
+			}
+		}
+		return entry.block, entry.txIds, nil
+	}
+
+	populatedBlock, txIds, err := ec.populateBlock(ctx, block, ec.inlineTransactions)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := ec.checkCanonical(ctx, block); err != nil {
+		return nil, nil, err
+	}
+	ec.cache.addBlock(block, populatedBlock, txIds)
+
+	return populatedBlock, txIds, nil
+}
+
+// checkCanonical returns an error wrapping ErrSnapshotRolledBack if
+// the snapshot block at the height of block is no longer block.
+func (ec *Client) checkCanonical(ctx context.Context, block *api.SnapshotBlock) error {
+	current, err := ec.c.GetSnapshotBlockByHeight(ctx, block.Height)
+	if err != nil {
+		return err
+	}
+	if current == nil || current.Hash != block.Hash {
+		return fmt.Errorf(
+			"%w: snapshot block %s at height %d was replaced",
+			ErrSnapshotRolledBack,
+			block.Hash.Hex(),
+			block.Height,
+		)
+	}
+
+	return nil
+}
+
+// populateBlock retrieves all account blocks included in a snapshot block.
+// If inline is true the transactions are returned in the block, otherwise
+// only their identifiers are returned.
+func (ec *Client) populateBlock(
+	ctx context.Context,
+	block *api.SnapshotBlock,
+	inline bool,
+) (*types.Block, []*types.TransactionIdentifier, error) {
 	// if InlineTransactions is true then parse all transactions in block
 	// otherwise only get transaction ids and return them as otherTransactions
 	txIds := []*types.TransactionIdentifier{}
@@ -182,29 +408,27 @@ func (ec *Client) Block(
 			Index: currentIdentifier.Index - 1,
 		}
 
-		for _, hashHeight := range block.SnapshotData {
-			hash := hashHeight.Hash
-			// retrieve all account blocks for each address in selected snapshot block
-			for {
-				account, err := ec.c.GetAccountBlockByHash(ctx, hash)
+		accountBlocks, err := ec.snapshotAccountBlocks(ctx, block)
+		if err != nil {
+			return nil, nil, err
+		}
+		if inline {
+			if err := ec.tokens.annotate(ctx, accountBlocks...); err != nil {
+				return nil, nil, err
+			}
+		}
+
+		for _, account := range accountBlocks {
+			if inline {
+				transaction, err := AccountBlockToTransaction(account, true)
 				if err != nil {
 					return nil, nil, err
 				}
-				if account.FirstSnapshotHash == nil || account.FirstSnapshotHash.Hex() != block.Hash.Hex() {
-					break
-				}
-				if ec.inlineTransactions {
-					transaction, err := AccountBlockToTransaction(account, true)
-					if err != nil {
-						return nil, nil, err
-					}
-					txs = append(txs, transaction)
-				} else {
-					txIds = append(txIds, &types.TransactionIdentifier{
-						Hash: hash.Hex(),
-					})
-				}
-				hash = account.PreviousHash
+				txs = append(txs, transaction)
+			} else {
+				txIds = append(txIds, &types.TransactionIdentifier{
+					Hash: account.Hash.Hex(),
+				})
 			}
 		}
 	}
@@ -237,9 +461,18 @@ func (ec *Client) getSnapshotBlock(
 			if err != nil {
 				return nil, err
 			}
-			return ec.c.GetSnapshotBlockByHash(ctx, hash)
+			return ec.snapshotBlockByHash(ctx, hash)
 		} else if blockIdentifier.Index != nil {
-			return ec.c.GetSnapshotBlockByHeight(ctx, uint64(*blockIdentifier.Index))
+			height := uint64(*blockIdentifier.Index)
+			if entry := ec.cache.byHeight(height); entry != nil {
+				return entry.snapshot, nil
+			}
+			block, err := ec.c.GetSnapshotBlockByHeight(ctx, height)
+			if err != nil {
+				return nil, err
+			}
+			ec.cache.addSnapshot(block)
+			return block, nil
 		}
 	}
 
@@ -248,7 +481,32 @@ func (ec *Client) getSnapshotBlock(
 		return nil, err
 	}
 
-	return ec.c.GetSnapshotBlockByHash(ctx, *hash)
+	block, err := ec.snapshotBlockByHash(ctx, *hash)
+	if err != nil {
+		return nil, err
+	}
+	ec.cache.setTip(block.Height)
+
+	return block, nil
+}
+
+// snapshotBlockByHash returns a SnapshotBlock from the
+// block cache or retrieves it from gvite.
+func (ec *Client) snapshotBlockByHash(
+	ctx context.Context,
+	hash viteTypes.Hash,
+) (*api.SnapshotBlock, error) {
+	if entry := ec.cache.byHash(hash); entry != nil {
+		return entry.snapshot, nil
+	}
+
+	block, err := ec.c.GetSnapshotBlockByHash(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	ec.cache.addSnapshot(block)
+
+	return block, nil
 }
 
 // Get BlockIdentifier for a SnapshotBlock
@@ -271,28 +529,30 @@ func (ec *Client) Balance(
 	blockIdentifier *types.PartialBlockIdentifier,
 ) (*types.AccountBalanceResponse, error) {
 
-	block, err := ec.getSnapshotBlock(ctx, blockIdentifier)
+	address, err := viteTypes.HexToAddress(account.Address)
 	if err != nil {
 		return nil, err
 	}
 
-	address, err := viteTypes.HexToAddress(account.Address)
+	if account.SubAccount != nil {
+		if account.SubAccount.Address != UnreceivedSubAccount {
+			return nil, fmt.Errorf("unknown sub account %s", account.SubAccount.Address)
+		}
+		return ec.unreceivedBalance(ctx, address, currencies, blockIdentifier)
+	}
+
+	block, err := ec.getSnapshotBlock(ctx, blockIdentifier)
 	if err != nil {
 		return nil, err
 	}
 
 	tokenIds := []viteTypes.TokenTypeId{}
-	for _, key := range currencies {
-		ttiMeta := key.Metadata["tti"]
-		if ttiMeta != nil {
-			ttiStr := ttiMeta.(string)
-			tti, err := viteTypes.HexToTokenTypeId(ttiStr)
-			if err != nil {
-				continue
-			}
-			tokenIds = append(tokenIds, tti)
+	for _, currency := range currencies {
+		tokenId, err := CurrencyTokenId(currency)
+		if err != nil {
+			return nil, err
 		}
-
+		tokenIds = append(tokenIds, tokenId)
 	}
 	if len(currencies) == 0 {
 		accountInfo, err := ec.c.GetAccountInfoByAddress(ctx, address)
@@ -309,67 +569,29 @@ func (ec *Client) Balance(
 		return nil, err
 	}
 
-	// if address has no balances
-	if confirmedBalances == nil {
-		// create zero balances for requested currencies
-		balances := []*types.Amount{}
-		for _, currency := range currencies {
-			balance := &types.Amount{
-				Value:    "0",
-				Currency: currency,
-			}
-			balances = append(balances, balance)
-		}
-		// if no currency was requested
-		if len(balances) == 0 {
-			// add VITE currency as default
-			balance := &types.Amount{
-				Value: "0",
-				Currency: &types.Currency{
-					Symbol:   "VITE",
-					Decimals: 18,
-					Metadata: map[string]interface{}{
-						"tti": "tti_5649544520544f4b454e6e40",
-					},
-				},
-			}
-			balances = append(balances, balance)
-		}
-		return &types.AccountBalanceResponse{
-			BlockIdentifier: &types.BlockIdentifier{
-				Hash:  block.Hash.Hex(),
-				Index: int64(block.Height),
-			},
-			Balances: balances,
-			// Metadata: map[string]interface{}{
-			// 	"address":    address.Hex(),
-			// 	"blockCount": 0,
-			// },
-		}, nil
+	// if address has no balances report zero balances for the requested
+	// currencies, or for VITE if no currency was requested
+	accountBalances := map[viteTypes.TokenTypeId]*big.Int{}
+	if confirmedBalances != nil {
+		accountBalances = (*confirmedBalances)[address]
+	} else if len(tokenIds) == 0 {
+		tokenIds = append(tokenIds, ledger.ViteTokenId)
 	}
 
-	accountBalances := (*confirmedBalances)[address]
+	tokenCurrencies, err := ec.tokens.Currencies(ctx, tokenIds)
+	if err != nil {
+		return nil, err
+	}
 
 	balances := make([]*types.Amount, len(tokenIds))
 	for i, tokenId := range tokenIds {
-		tokenInfo, err := ec.c.GetTokenInfoById(ctx, tokenId.Hex())
-		if err != nil {
-			return nil, err
-		}
 		value := accountBalances[tokenId]
 		if value == nil {
 			value = big.NewInt(0)
 		}
-		currency := &types.Currency{
-			Symbol:   tokenInfo.TokenSymbol,
-			Decimals: int32(tokenInfo.Decimals),
-			Metadata: map[string]interface{}{
-				"tti": tokenId.Hex(),
-			},
-		}
 		balances[i] = &types.Amount{
 			Value:    value.String(),
-			Currency: currency,
+			Currency: tokenCurrencies[i],
 		}
 	}
 
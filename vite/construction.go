@@ -1,14 +1,17 @@
 package vite
 
 import (
+	"bytes"
 	"context"
 	"encoding/base64"
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/coinbase/rosetta-sdk-go/types"
 	viteTypes "github.com/vitelabs/go-vite/common/types"
 	"github.com/vitelabs/go-vite/rpcapi/api"
+	"github.com/vitelabs/go-vite/vm/util"
 )
 
 func ConstructionPreprocess(
@@ -33,6 +36,14 @@ func ConstructionPreprocess(
 		if err == nil {
 			usePow = strconv.FormatBool(usePowBool)
 		}
+		if strings.EqualFold(usePowStr, UsePowAuto) {
+			usePow = UsePowAuto
+		}
+	}
+
+	contractAbi, constructorArgs, err := encodeContractCall(description, metadata)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	options := &ConstructionOptions{
@@ -43,6 +54,9 @@ func ConstructionPreprocess(
 		FetchPreviousBlock: fetchPreviousHash,
 		UsePow:             usePow,
 		Data:               description.Data,
+		ContractAbi:        contractAbi,
+		ConstructorArgs:    constructorArgs,
+		Contract:           description.Contract,
 	}
 
 	requiredPublicKeys := []*types.AccountIdentifier{
@@ -52,6 +66,66 @@ func ConstructionPreprocess(
 	return options, requiredPublicKeys, nil
 }
 
+// encodeContractCall ABI-encodes the contract call in metadata into
+// the description data and returns the contract abi. An empty abi
+// is returned if metadata does not contain a contract abi.
+// For CREATE_CONTRACT the constructor arguments are encoded and
+// returned instead, the method name must then be omitted.
+func encodeContractCall(
+	description *TransactionDescription,
+	metadata map[string]interface{},
+) (string, []byte, error) {
+	abiValue, ok := metadata[MetadataContractAbiKey]
+	if !ok {
+		return "", nil, nil
+	}
+
+	contractAbi, err := ContractAbiFromMetadata(abiValue)
+	if err != nil {
+		return "", nil, err
+	}
+
+	args := []interface{}{}
+	if value, ok := metadata[MetadataMethodArgsKey]; ok && value != nil {
+		args, ok = value.([]interface{})
+		if !ok {
+			return "", nil, fmt.Errorf("%s must be an array", MetadataMethodArgsKey)
+		}
+	}
+
+	methodName, _ := metadata[MetadataMethodNameKey].(string)
+	switch description.OperationType {
+	case CreateContractOpType:
+		if len(methodName) > 0 {
+			return "", nil, fmt.Errorf("%s cannot be used with %s", MetadataMethodNameKey, CreateContractOpType)
+		}
+		constructorArgs, err := EncodeContractCall(contractAbi, "", args)
+		if err != nil {
+			return "", nil, err
+		}
+		return contractAbi, constructorArgs, nil
+	case RequestOpType:
+		if len(description.Data) > 0 {
+			return "", nil, fmt.Errorf("%s and operation data cannot be used together", MetadataContractAbiKey)
+		}
+		if len(methodName) == 0 {
+			return "", nil, fmt.Errorf("missing %s", MetadataMethodNameKey)
+		}
+		data, err := EncodeContractCall(contractAbi, methodName, args)
+		if err != nil {
+			return "", nil, err
+		}
+		description.Data = data
+		return contractAbi, nil, nil
+	default:
+		return "", nil, fmt.Errorf(
+			"contract calls require a %s or %s operation",
+			RequestOpType,
+			CreateContractOpType,
+		)
+	}
+}
+
 func (ec *Client) ConstructionMetadata(
 	ctx context.Context,
 	options *ConstructionOptions,
@@ -75,53 +149,91 @@ func (ec *Client) ConstructionMetadata(
 		}
 	}
 
+	// the data of a create contract block is prefixed with the
+	// contract parameters, PoW is computed for the final data
+	data := options.Data
+	if options.Contract != nil {
+		data = createContractData(options.Contract, options.Data, options.ConstructorArgs)
+	}
+
 	metadata := &ConstructionMetadata{
-		Height:       height,
-		PreviousHash: accountBlock.Hash.Hex(),
+		Height:          height,
+		PreviousHash:    accountBlock.Hash.Hex(),
+		Data:            data,
+		ContractAbi:     options.ContractAbi,
+		ConstructorArgs: options.ConstructorArgs,
 	}
 
 	usePow, err := strconv.ParseBool(options.UsePow)
-	if err == nil && usePow {
-		toAddress, err := viteTypes.HexToAddress(options.ToAccount.Address)
+	if err != nil {
+		usePow = false
+	}
+	if options.UsePow == UsePowAuto {
+		decision, err := ec.powDecision(ctx, address, options.OperationType, data)
 		if err != nil {
 			return nil, err
 		}
+		metadata.PowDecision = decision
+		usePow = decision.UsePow
+	}
 
+	if usePow {
 		blockType, err := OperationTypeToBlockType(options.OperationType)
 		if err != nil {
 			return nil, err
 		}
 
-		param := &api.GetPoWDifficultyParam{
-			SelfAddr:  address,
-			PrevHash:  prevHash,
-			BlockType: blockType,
-			ToAddr:    &toAddress,
-			Data:      options.Data,
+		// the contract address is derived when creating a contract
+		var toAddress *viteTypes.Address
+		if options.OperationType != CreateContractOpType {
+			to, err := viteTypes.HexToAddress(options.ToAccount.Address)
+			if err != nil {
+				return nil, err
+			}
+			toAddress = &to
 		}
 
-		// if options.Data != nil {
-		// 	param.Data = options.Data
-		// }
-
-		result, err := ec.c.GetPoWDifficulty(ctx, param)
+		metadata.Difficulty, metadata.Nonce, err = ec.solvePoW(ctx, address, prevHash, blockType, toAddress, data)
 		if err != nil {
 			return nil, err
 		}
+	}
 
-		if len(result.Difficulty) > 0 {
-			nonceHash := viteTypes.DataHash(append(address.Bytes(), prevHash.Bytes()...))
-			nonce, err := ec.c.GetPoWNonce(ctx, result.Difficulty, nonceHash.Hex())
-			if err != nil {
-				return nil, err
-			}
+	return metadata, nil
+}
 
-			metadata.Difficulty = &result.Difficulty
-			metadata.Nonce = &nonce
-		}
+// solvePoW retrieves the PoW difficulty of a block following prevHash,
+// which must be the latest account block of address, and computes its
+// nonce. Nil is returned if gvite requires no PoW.
+func (ec *Client) solvePoW(
+	ctx context.Context,
+	address viteTypes.Address,
+	prevHash viteTypes.Hash,
+	blockType byte,
+	toAddress *viteTypes.Address,
+	data []byte,
+) (*string, *string, error) {
+	result, err := ec.c.GetPoWDifficulty(ctx, &api.GetPoWDifficultyParam{
+		SelfAddr:  address,
+		PrevHash:  prevHash,
+		BlockType: blockType,
+		ToAddr:    toAddress,
+		Data:      data,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(result.Difficulty) == 0 {
+		return nil, nil, nil
 	}
 
-	return metadata, nil
+	nonceHash := viteTypes.DataHash(append(address.Bytes(), prevHash.Bytes()...))
+	nonce, err := ec.powNonce(ctx, result.Difficulty, nonceHash)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &result.Difficulty, &nonce, nil
 }
 
 func CreateAccountBlock(
@@ -165,17 +277,38 @@ func CreateAccountBlock(
 		accountBlock.FromAddress = checkFrom
 	}
 
-	toAdd := description.ToAccount.Address
-	// Ensure valid to address
-	checkTo, err := viteTypes.HexToAddress(toAdd)
-	if err != nil {
-		return nil, fmt.Errorf("%s is not a valid address", toAdd)
-	}
-	accountBlock.ToAddress = checkTo
+	if description.Contract != nil {
+		// the contract address is derived from the new block
+		accountBlock.ToAddress = util.NewContractAddress(address, metadata.Height+1, prevHash)
 
-	// if description.Data != nil {
-	// 	accountBlock.Data = []byte(*description.Data)
-	// }
+		// the data PoW was computed for in metadata must
+		// match the contract of the operations
+		data := createContractData(description.Contract, description.Data, metadata.ConstructorArgs)
+		if len(metadata.Data) > 0 && !bytes.Equal(data, metadata.Data) {
+			return nil, fmt.Errorf("create contract data does not match metadata data")
+		}
+		accountBlock.Data = data
+	} else {
+		// data encoded during preprocess, e.g. an ABI-encoded contract call
+		if len(metadata.Data) > 0 {
+			if len(description.Data) > 0 && !bytes.Equal(description.Data, metadata.Data) {
+				return nil, fmt.Errorf("operation data does not match metadata data")
+			}
+			accountBlock.Data = metadata.Data
+		}
+
+		if len(metadata.ConstructorArgs) > 0 {
+			return nil, fmt.Errorf("constructor arguments require a %s operation", CreateContractOpType)
+		}
+
+		toAdd := description.ToAccount.Address
+		// Ensure valid to address
+		checkTo, err := viteTypes.HexToAddress(toAdd)
+		if err != nil {
+			return nil, fmt.Errorf("%s is not a valid address", toAdd)
+		}
+		accountBlock.ToAddress = checkTo
+	}
 
 	if metadata.Difficulty != nil {
 		accountBlock.Difficulty = metadata.Difficulty
@@ -220,3 +353,21 @@ func CreateAccountBlock(
 
 	return accountBlock, nil
 }
+
+// createContractData prefixes the bytecode and the constructor arguments
+// of a contract with its create contract parameters.
+func createContractData(
+	contract *ContractDescription,
+	bytecode []byte,
+	constructorArgs []byte,
+) []byte {
+	code := append(append([]byte{}, bytecode...), constructorArgs...)
+	return util.GetCreateContractData(
+		code,
+		util.SolidityPPContractType,
+		contract.ConfirmTimes,
+		contract.SeedCount,
+		contract.QuotaMultiplier,
+		contract.Gid,
+	)
+}
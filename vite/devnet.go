@@ -0,0 +1,47 @@
+package vite
+
+import (
+	"fmt"
+
+	"github.com/coinbase/rosetta-sdk-go/utils"
+)
+
+const (
+	// DefaultDevnetNetworkID is the default network id of a devnet,
+	// gvite reserves 1 for the mainnet and 2 for the testnet.
+	DefaultDevnetNetworkID = 3
+
+	// DevnetBootstrapFile is the name of the bootstrap
+	// balances file generated for a devnet.
+	DevnetBootstrapFile = "bootstrap_balances.json"
+)
+
+// GenerateDevnetGviteConfig creates the gvite config of a devnet from a
+// base gvite config. The genesis file, the network id, the RPC and
+// WebSocket ports and the public modules of the base gvite config are
+// replaced with the values of config, since rosetta-vite connects to
+// gvite with them.
+func GenerateDevnetGviteConfig(
+	baseConfigFile string,
+	config *GviteConfig,
+	outputFile string,
+) error {
+	gviteConfig := map[string]interface{}{}
+	if err := utils.LoadAndParse(baseConfigFile, &gviteConfig); err != nil {
+		return fmt.Errorf("%w: could not load gvite config", err)
+	}
+
+	gviteConfig["GenesisFile"] = config.GenesisFile
+	gviteConfig["NetID"] = config.NetID
+	gviteConfig["RPCEnabled"] = true
+	gviteConfig["HttpPort"] = config.HTTPPort
+	gviteConfig["WSEnabled"] = true
+	gviteConfig["WSPort"] = config.WSPort
+	gviteConfig["PublicModules"] = config.PublicModules
+
+	if err := utils.SerializeAndWrite(outputFile, gviteConfig); err != nil {
+		return fmt.Errorf("%w: could not write gvite config", err)
+	}
+
+	return nil
+}
@@ -7,4 +7,9 @@ var (
 	ErrCallParametersInvalid = errors.New("call parameters invalid")
 	ErrCallOutputMarshal     = errors.New("call output marshal")
 	ErrCallMethodInvalid     = errors.New("call method invalid")
+	ErrSnapshotRolledBack    = errors.New("snapshot chain rolled back")
+	ErrTransactionNotFound   = errors.New("transaction not found")
+	ErrBlockOrphaned         = errors.New("block orphaned")
+	ErrSearchRequestInvalid  = errors.New("search request invalid")
+	ErrCurrencyInvalid       = errors.New("currency invalid")
 )
@@ -9,8 +9,7 @@ import (
 	"os"
 	"os/exec"
 	"strings"
-
-	"golang.org/x/sync/errgroup"
+	"sync"
 )
 
 const (
@@ -34,12 +33,13 @@ func logPipe(pipe io.ReadCloser, identifier string) error {
 	}
 }
 
-// StartGvite starts a gvite daemon in another goroutine
-// and logs the results to the console.
-func StartGvite(ctx context.Context, arguments string, g *errgroup.Group) error {
+// runGvite starts the gvite daemon in binary, logs its output to the console
+// and waits until it exits. started is called once the process is
+// running. gvite is interrupted when the context is canceled.
+func runGvite(ctx context.Context, binary string, arguments string, started func()) error {
 	parsedArgs := strings.Split(arguments, " ")
 	cmd := exec.Command(
-		"/app/gvite",
+		binary,
 		parsedArgs...,
 	) // #nosec G204
 
@@ -53,24 +53,35 @@ func StartGvite(ctx context.Context, arguments string, g *errgroup.Group) error
 		return err
 	}
 
-	g.Go(func() error {
-		return logPipe(stdout, gviteLogger)
-	})
-
-	g.Go(func() error {
-		return logPipe(stderr, gviteStdErrLogger)
-	})
-
 	if err := cmd.Start(); err != nil {
 		return fmt.Errorf("%w: unable to start gvite", err)
 	}
+	started()
 
-	g.Go(func() error {
-		<-ctx.Done()
+	// The pipes must be read until gvite exits
+	// before waiting for the process.
+	var pipes sync.WaitGroup
+	pipes.Add(2) //nolint:gomnd
+	go func() {
+		defer pipes.Done()
+		_ = logPipe(stdout, gviteLogger)
+	}()
+	go func() {
+		defer pipes.Done()
+		_ = logPipe(stderr, gviteStdErrLogger)
+	}()
 
-		log.Println("sending interrupt to gvite")
-		return cmd.Process.Signal(os.Interrupt)
-	})
+	exited := make(chan struct{})
+	defer close(exited)
+	go func() {
+		select {
+		case <-ctx.Done():
+			log.Println("sending interrupt to gvite")
+			_ = cmd.Process.Signal(os.Interrupt)
+		case <-exited:
+		}
+	}()
 
+	pipes.Wait()
 	return cmd.Wait()
 }
@@ -0,0 +1,153 @@
+package vite
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"text/template"
+)
+
+const (
+	// DefaultGviteBinary is the location of the gvite binary.
+	DefaultGviteBinary = "/app/gvite"
+
+	// GviteConfigFile is the name of the generated gvite config file.
+	GviteConfigFile = "node_config.json"
+
+	// DefaultGviteHTTPPort is the default port of the gvite RPC server.
+	DefaultGviteHTTPPort = 48132
+
+	// DefaultGviteWSPort is the default port of the gvite WebSocket server.
+	DefaultGviteWSPort = 41420
+
+	// DefaultGviteLogLevel is the default log level of gvite.
+	DefaultGviteLogLevel = "info"
+
+	// MainnetNetworkID is the gvite network id of the mainnet.
+	MainnetNetworkID = 1
+
+	// TestnetNetworkID is the gvite network id of the testnet.
+	TestnetNetworkID = 2
+)
+
+var (
+	// DefaultGvitePublicModules are the gvite RPC modules used by rosetta-vite.
+	DefaultGvitePublicModules = []string{"ledger", "net", "contract", "util"}
+
+	// DefaultGviteBootSeeds are the bootnodes of the mainnet and testnet.
+	DefaultGviteBootSeeds = []string{"https://bootnodes.vite.net/bootmainnet.json"}
+)
+
+// GviteConfig contains the values a gvite config template is rendered with.
+type GviteConfig struct {
+	DataDir       string
+	NetID         int
+	GenesisFile   string
+	HTTPPort      int
+	WSPort        int
+	PublicModules []string
+	BootSeeds     []string
+	LogLevel      string
+}
+
+// DefaultGviteConfigTemplate is the template of the gvite config,
+// values are inserted with the json function so they are escaped.
+const DefaultGviteConfigTemplate = `{
+  "Identity": "rosetta-vite",
+  "NetID": {{ json .NetID }},
+  "DataDir": {{ json .DataDir }},
+  {{- if .GenesisFile }}
+  "GenesisFile": {{ json .GenesisFile }},
+  {{- end }}
+  "ListenInterface": "0.0.0.0",
+  "Port": 8483,
+  "FilePort": 8484,
+  "MaxPeers": 10,
+  "MinPeers": 5,
+  "MaxInboundRatio": 2,
+  "MaxPendingPeers": 5,
+  "BootSeeds": {{ json .BootSeeds }},
+  "Discover": {{ json (gt (len .BootSeeds) 0) }},
+  "RPCEnabled": true,
+  "HttpHost": "0.0.0.0",
+  "HttpPort": {{ json .HTTPPort }},
+  "WSEnabled": true,
+  "WSHost": "0.0.0.0",
+  "WSPort": {{ json .WSPort }},
+  "HttpVirtualHosts": [],
+  "IPCEnabled": true,
+  "PublicModules": {{ json .PublicModules }},
+  "Miner": false,
+  "LogLevel": {{ json .LogLevel }}
+}
+`
+
+// GviteArguments returns the arguments to start
+// a gvite instance with a config file.
+func GviteArguments(configFile string) string {
+	return fmt.Sprintf("--config=%s", configFile)
+}
+
+// RenderGviteConfig renders the gvite config template in templateFile,
+// or DefaultGviteConfigTemplate if templateFile is empty, with config.
+// The rendered config must be valid JSON.
+func RenderGviteConfig(templateFile string, config *GviteConfig) ([]byte, error) {
+	text := DefaultGviteConfigTemplate
+	if len(templateFile) > 0 {
+		content, err := ioutil.ReadFile(path.Clean(templateFile))
+		if err != nil {
+			return nil, fmt.Errorf("%w: unable to read gvite config template %s", err, templateFile)
+		}
+		text = string(content)
+	}
+
+	tmpl, err := template.New("gvite").
+		Option("missingkey=error").
+		Funcs(template.FuncMap{"json": marshalTemplateValue}).
+		Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unable to parse gvite config template", err)
+	}
+
+	var rendered bytes.Buffer
+	if err := tmpl.Execute(&rendered, config); err != nil {
+		return nil, fmt.Errorf("%w: unable to render gvite config template", err)
+	}
+
+	if !json.Valid(rendered.Bytes()) {
+		return nil, fmt.Errorf("rendered gvite config is not valid JSON")
+	}
+
+	return rendered.Bytes(), nil
+}
+
+// GenerateGviteConfig renders a gvite config and writes it to outputFile.
+func GenerateGviteConfig(templateFile string, config *GviteConfig, outputFile string) error {
+	rendered, err := RenderGviteConfig(templateFile, config)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(path.Dir(outputFile), os.ModePerm); err != nil {
+		return fmt.Errorf("%w: unable to create gvite config directory", err)
+	}
+
+	if err := ioutil.WriteFile(outputFile, rendered, 0600); err != nil { //nolint:gomnd
+		return fmt.Errorf("%w: could not write gvite config", err)
+	}
+
+	return nil
+}
+
+// marshalTemplateValue encodes a template value as JSON.
+func marshalTemplateValue(value interface{}) (string, error) {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+
+	return string(encoded), nil
+}
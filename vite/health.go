@@ -0,0 +1,150 @@
+package vite
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/azbuky/rosetta-vite/vite/rpc"
+)
+
+const (
+	// DefaultHealthMaxBlockAge is the default maximum age of
+	// the latest snapshot block of a ready node.
+	DefaultHealthMaxBlockAge = 2 * time.Minute
+
+	// DefaultHealthMaxSyncLag is the default maximum number of
+	// snapshot blocks a ready node can be behind its peers.
+	DefaultHealthMaxSyncLag = uint64(10)
+
+	// syncDoneState is the net_syncInfo state of a synced node.
+	syncDoneState = 2
+
+	// Names of the readiness checks.
+	gviteHealthCheck    = "gvite"
+	rpcHealthCheck      = "rpc"
+	syncHealthCheck     = "sync"
+	blockAgeHealthCheck = "block_age"
+)
+
+// HealthThresholds determines when a node is ready.
+type HealthThresholds struct {
+	// MaxBlockAge is the maximum age of the latest snapshot block
+	MaxBlockAge time.Duration
+
+	// MaxSyncLag is the maximum number of snapshot blocks the
+	// node can be behind its peers while it is still syncing
+	MaxSyncLag uint64
+}
+
+// HealthCheck is the result of a single readiness check.
+type HealthCheck struct {
+	Healthy bool   `json:"healthy"`
+	Message string `json:"message,omitempty"`
+}
+
+// Readiness is the result of all readiness checks of a node.
+// Gvite is the status of the local gvite process, if any.
+type Readiness struct {
+	Ready  bool                    `json:"ready"`
+	Checks map[string]*HealthCheck `json:"checks"`
+	Gvite  *GviteStatus            `json:"gvite,omitempty"`
+}
+
+// Readiness checks that gvite is running, reachable, synced
+// and producing snapshot blocks within the thresholds.
+func (ec *Client) Readiness(ctx context.Context, thresholds *HealthThresholds) *Readiness {
+	readiness := &Readiness{
+		Checks: map[string]*HealthCheck{},
+	}
+
+	if ec.supervisor != nil {
+		readiness.Gvite = ec.supervisor.Status()
+		if err := ec.GviteReady(); err != nil {
+			readiness.Checks[gviteHealthCheck] = &HealthCheck{Message: err.Error()}
+		} else {
+			readiness.Checks[gviteHealthCheck] = &HealthCheck{Healthy: true}
+		}
+	}
+
+	nodeInfo, err := ec.c.GetNodeInfo(ctx)
+	if err == nil {
+		blockCall, block := rpc.NewGetSnapshotBlockByHeightCall(nodeInfo.Height)
+		syncInfoCall, syncInfo := rpc.NewGetSyncInfoCall()
+		err = ec.c.BatchCallContext(ctx, []*rpc.BatchCall{blockCall, syncInfoCall})
+		if err == nil {
+			readiness.Checks[syncHealthCheck] = syncCheck(syncInfoCall.Error, syncInfo.State, syncInfo.To, syncInfo.Current, thresholds)
+			readiness.Checks[blockAgeHealthCheck] = blockAgeCheck(blockCall.Error, block.Timestamp, thresholds)
+		}
+	}
+
+	if err != nil {
+		message := fmt.Sprintf("gvite unreachable: %s", err.Error())
+		readiness.Checks[rpcHealthCheck] = &HealthCheck{Message: message}
+		readiness.Checks[syncHealthCheck] = &HealthCheck{Message: message}
+		readiness.Checks[blockAgeHealthCheck] = &HealthCheck{Message: message}
+		return readiness
+	}
+
+	readiness.Checks[rpcHealthCheck] = &HealthCheck{Healthy: true}
+	readiness.Ready = true
+	for _, check := range readiness.Checks {
+		readiness.Ready = readiness.Ready && check.Healthy
+	}
+
+	return readiness
+}
+
+// syncCheck passes when gvite is synced, or when it is
+// syncing less than MaxSyncLag blocks behind its peers.
+func syncCheck(
+	err error,
+	state uint,
+	target string,
+	current string,
+	thresholds *HealthThresholds,
+) *HealthCheck {
+	if err != nil {
+		return &HealthCheck{Message: err.Error()}
+	}
+	if state == syncDoneState {
+		return &HealthCheck{Healthy: true}
+	}
+
+	targetHeight, err := strconv.ParseUint(target, 10, 64)
+	if err != nil {
+		return &HealthCheck{Message: fmt.Sprintf("invalid sync target %s", target)}
+	}
+	currentHeight, err := strconv.ParseUint(current, 10, 64)
+	if err != nil {
+		return &HealthCheck{Message: fmt.Sprintf("invalid sync height %s", current)}
+	}
+
+	lag := uint64(0)
+	if targetHeight > currentHeight {
+		lag = targetHeight - currentHeight
+	}
+	message := fmt.Sprintf("sync state %d, %d blocks behind", state, lag)
+
+	return &HealthCheck{
+		Healthy: lag <= thresholds.MaxSyncLag,
+		Message: message,
+	}
+}
+
+// blockAgeCheck passes when the latest snapshot
+// block is at most MaxBlockAge old.
+func blockAgeCheck(err error, timestamp int64, thresholds *HealthThresholds) *HealthCheck {
+	if err != nil {
+		return &HealthCheck{Message: err.Error()}
+	}
+
+	age := time.Since(time.Unix(timestamp, 0)).Truncate(time.Second)
+	message := fmt.Sprintf("latest snapshot block is %s old", age)
+
+	return &HealthCheck{
+		Healthy: age <= thresholds.MaxBlockAge,
+		Message: message,
+	}
+}
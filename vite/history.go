@@ -0,0 +1,155 @@
+package vite
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+
+	viteTypes "github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/rpcapi/api"
+)
+
+const (
+	// DefaultHistoryLimit is the default number of
+	// transactions returned in a history page.
+	DefaultHistoryLimit = int64(25)
+
+	// MaxHistoryLimit is the maximum number of
+	// transactions returned in a history page.
+	MaxHistoryLimit = int64(1000)
+
+	// historyScanLimit is the maximum number of account blocks scanned
+	// for a single history page. When filtering by token a page may be
+	// returned with fewer transactions and a cursor to continue from.
+	historyScanLimit = uint64(5000)
+)
+
+// AccountHistory is a page of the transactions of an account,
+// ordered by descending height. NextHeight and NextHash point
+// to the first block of the next page, they are not set once
+// the beginning of the account chain is reached.
+type AccountHistory struct {
+	Transactions []*types.Transaction
+	NextHeight   *uint64
+	NextHash     *viteTypes.Hash
+}
+
+// AccountHistory returns up to limit transactions of an account,
+// starting at the block with the given height or hash, or at the
+// latest block if neither is set. If tokenId is set only the
+// transactions of that token are returned.
+func (ec *Client) AccountHistory(
+	ctx context.Context,
+	account *types.AccountIdentifier,
+	height *uint64,
+	hash *viteTypes.Hash,
+	tokenId *viteTypes.TokenTypeId,
+	limit int64,
+) (*AccountHistory, error) {
+	address, err := viteTypes.HexToAddress(account.Address)
+	if err != nil {
+		return nil, fmt.Errorf("%s is not a valid address", account.Address)
+	}
+
+	if limit <= 0 {
+		limit = DefaultHistoryLimit
+	}
+
+	if hash == nil && height != nil {
+		block, err := ec.c.GetAccountBlockByHeight(ctx, address, *height)
+		if err != nil {
+			return nil, err
+		}
+		if block.Hash.IsZero() {
+			return nil, fmt.Errorf("block %d of %s not found", *height, account.Address)
+		}
+		hash = &block.Hash
+	}
+
+	batchSize := ec.blockBatchSize
+	if batchSize == 0 {
+		batchSize = DefaultBlockBatchSize
+	}
+	count := uint64(limit)
+	if count > batchSize {
+		count = batchSize
+	}
+
+	history := &AccountHistory{
+		Transactions: []*types.Transaction{},
+	}
+	for scanned := uint64(0); scanned < historyScanLimit; scanned += count {
+		blocks, err := ec.c.GetAccountBlocks(ctx, address, hash, count)
+		if err != nil {
+			return nil, err
+		}
+		if len(blocks) > 0 && blocks[0].AccountAddress != address {
+			return nil, fmt.Errorf("block %s does not belong to %s", blocks[0].Hash, account.Address)
+		}
+
+		matching := []*api.AccountBlock{}
+		for _, block := range blocks {
+			if tokenId != nil && block.TokenId != *tokenId {
+				continue
+			}
+			matching = append(matching, block)
+		}
+		if err := ec.tokens.annotate(ctx, matching...); err != nil {
+			return nil, err
+		}
+
+		for _, block := range matching {
+			transaction, err := AccountBlockToTransaction(block, true)
+			if err != nil {
+				return nil, err
+			}
+			history.Transactions = append(history.Transactions, transaction)
+
+			if int64(len(history.Transactions)) == limit {
+				// the next page starts after the last returned block
+				if err := history.setNext(block); err != nil {
+					return nil, err
+				}
+				return history, nil
+			}
+		}
+
+		if uint64(len(blocks)) < count {
+			history.NextHeight = nil
+			history.NextHash = nil
+			return history, nil
+		}
+		if err := history.setNext(blocks[len(blocks)-1]); err != nil {
+			return nil, err
+		}
+		if history.NextHash == nil {
+			return history, nil
+		}
+		hash = history.NextHash
+	}
+
+	return history, nil
+}
+
+// setNext sets the cursor of the next page to
+// the block preceding block in the account chain.
+func (h *AccountHistory) setNext(block *api.AccountBlock) error {
+	h.NextHeight = nil
+	h.NextHash = nil
+	if block.PreviousHash.IsZero() {
+		return nil
+	}
+
+	height, err := strconv.ParseUint(block.Height, 10, 64)
+	if err != nil {
+		return err
+	}
+	nextHeight := height - 1
+	nextHash := block.PreviousHash
+	h.NextHeight = &nextHeight
+	h.NextHash = &nextHash
+
+	return nil
+}
@@ -0,0 +1,498 @@
+package vite
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+
+	viteTypes "github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/rpcapi/api"
+)
+
+const (
+	// SearchDefaultLimit is the number of transactions returned
+	// by /search/transactions when no limit is provided.
+	SearchDefaultLimit = int64(100)
+
+	// SearchMaxLimit is the maximum number of transactions returned
+	// by /search/transactions in a single call.
+	SearchMaxLimit = int64(1000)
+)
+
+var (
+	transactionKeyPrefix = []byte("tx/")
+	addressKeyPrefix     = []byte("addr/")
+	hashKeyPrefix        = []byte("hash/")
+
+	transactionCountKey = []byte("transaction-count")
+)
+
+// Indexer stores every account block included in a snapshot block
+// tracked by the Tracker so that transactions can be searched
+// without scanning the whole chain.
+type Indexer struct {
+	client  *Client
+	tracker *Tracker
+	db      *leveldb.DB
+}
+
+// NewIndexer creates an Indexer that is updated by the provided
+// tracker. It must be created before the tracker is started.
+func NewIndexer(client *Client, tracker *Tracker) *Indexer {
+	indexer := &Indexer{
+		client:  client,
+		tracker: tracker,
+		db:      tracker.db,
+	}
+	tracker.handlers = append(tracker.handlers, indexer)
+
+	return indexer
+}
+
+// countTransactions counts the indexed transactions in a key range
+// without decoding them.
+func (i *Indexer) countTransactions(keyRange *util.Range) (uint64, error) {
+	iter := i.db.NewIterator(keyRange, nil)
+	defer iter.Release()
+
+	count := uint64(0)
+	for iter.Next() {
+		count++
+	}
+
+	return count, iter.Error()
+}
+
+// addTransactionCount adds delta to the stored transaction count,
+// the count is stored with the first indexed block.
+func (i *Indexer) addTransactionCount(batch *leveldb.Batch, delta int64) error {
+	count, err := i.tracker.getUint64(transactionCountKey)
+	if err != nil {
+		return err
+	}
+
+	batch.Put(transactionCountKey, encodeUint64(uint64(int64(count)+delta)))
+	return nil
+}
+
+// addBlock stores all transactions of a snapshot block.
+func (i *Indexer) addBlock(
+	ctx context.Context,
+	batch *leveldb.Batch,
+	snapshotBlock *api.SnapshotBlock,
+) error {
+	block, _, err := i.client.populateBlock(ctx, snapshotBlock, true)
+	if err != nil {
+		return err
+	}
+
+	height := snapshotBlock.Height
+	for index, transaction := range block.Transactions {
+		txKey := transactionKey(height, uint32(index))
+		value, err := json.Marshal(&types.BlockTransaction{
+			BlockIdentifier: block.BlockIdentifier,
+			Transaction:     transaction,
+		})
+		if err != nil {
+			return err
+		}
+
+		batch.Put(txKey, value)
+		batch.Put(hashKey(transaction.TransactionIdentifier.Hash), txKey)
+		for _, address := range transactionAddresses(transaction) {
+			batch.Put(addressKey(address, txKey), nil)
+		}
+	}
+
+	return i.addTransactionCount(batch, int64(len(block.Transactions)))
+}
+
+// removeBlock deletes all transactions of the snapshot block at height.
+func (i *Indexer) removeBlock(batch *leveldb.Batch, height uint64) error {
+	iter := i.db.NewIterator(util.BytesPrefix(blockTransactionsPrefix(height)), nil)
+	defer iter.Release()
+	removed := int64(0)
+	for iter.Next() {
+		var blockTransaction types.BlockTransaction
+		if err := json.Unmarshal(iter.Value(), &blockTransaction); err != nil {
+			return err
+		}
+
+		txKey := append([]byte{}, iter.Key()...)
+		transaction := blockTransaction.Transaction
+		batch.Delete(txKey)
+		batch.Delete(hashKey(transaction.TransactionIdentifier.Hash))
+		for _, address := range transactionAddresses(transaction) {
+			batch.Delete(addressKey(address, txKey))
+		}
+		removed++
+	}
+	if err := iter.Error(); err != nil {
+		return err
+	}
+
+	return i.addTransactionCount(batch, -removed)
+}
+
+// Search returns the indexed transactions matching the request,
+// most recent first.
+func (i *Indexer) Search(
+	ctx context.Context,
+	request *types.SearchTransactionsRequest,
+) (*types.SearchTransactionsResponse, error) {
+	indexedHeight, err := i.tracker.TrackedHeight()
+	if err != nil {
+		return nil, err
+	}
+
+	maxBlock := indexedHeight
+	if request.MaxBlock != nil && uint64(*request.MaxBlock) < maxBlock {
+		maxBlock = uint64(*request.MaxBlock)
+	}
+
+	offset := int64(0)
+	if request.Offset != nil {
+		offset = *request.Offset
+	}
+
+	limit := SearchDefaultLimit
+	if request.Limit != nil {
+		limit = *request.Limit
+	}
+	if limit > SearchMaxLimit {
+		limit = SearchMaxLimit
+	}
+
+	filter, err := newSearchFilter(request)
+	if err != nil {
+		return nil, err
+	}
+
+	if !filter.or && filter.hash == nil && !filter.hasOperationConditions() {
+		return i.searchAll(indexedHeight, maxBlock, offset, limit)
+	}
+
+	transactions := []*types.BlockTransaction{}
+	total := int64(0)
+	err = i.candidates(request, filter, maxBlock, func(blockTransaction *types.BlockTransaction) {
+		if !filter.match(blockTransaction.Transaction) {
+			return
+		}
+		if total >= offset && total < offset+limit {
+			transactions = append(transactions, blockTransaction)
+		}
+		total++
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return searchResponse(transactions, offset, total), nil
+}
+
+// searchAll returns a page of all indexed transactions up to maxBlock.
+// The total count is derived from the stored transaction count, only
+// the transactions of the page are decoded.
+func (i *Indexer) searchAll(
+	indexedHeight uint64,
+	maxBlock uint64,
+	offset int64,
+	limit int64,
+) (*types.SearchTransactionsResponse, error) {
+	count, err := i.tracker.getUint64(transactionCountKey)
+	if err != nil {
+		return nil, err
+	}
+	if maxBlock < indexedHeight {
+		above, err := i.countTransactions(&util.Range{
+			Start: transactionKey(maxBlock+1, 0),
+			Limit: util.BytesPrefix(transactionKeyPrefix).Limit,
+		})
+		if err != nil {
+			return nil, err
+		}
+		count -= above
+	}
+	total := int64(count)
+
+	transactions := []*types.BlockTransaction{}
+	iter := i.db.NewIterator(&util.Range{
+		Start: transactionKeyPrefix,
+		Limit: transactionKey(maxBlock+1, 0),
+	}, nil)
+	defer iter.Release()
+	position := int64(0)
+	for ok := iter.Last(); ok && position < offset+limit; ok = iter.Prev() {
+		if position >= offset {
+			var blockTransaction types.BlockTransaction
+			if err := json.Unmarshal(iter.Value(), &blockTransaction); err != nil {
+				return nil, err
+			}
+			transactions = append(transactions, &blockTransaction)
+		}
+		position++
+	}
+	if err := iter.Error(); err != nil {
+		return nil, err
+	}
+
+	return searchResponse(transactions, offset, total), nil
+}
+
+// searchResponse returns a page of transactions starting at offset,
+// with the offset of the next page if more transactions match.
+func searchResponse(
+	transactions []*types.BlockTransaction,
+	offset int64,
+	total int64,
+) *types.SearchTransactionsResponse {
+	response := &types.SearchTransactionsResponse{
+		Transactions: transactions,
+		TotalCount:   total,
+	}
+	if offset+int64(len(transactions)) < total {
+		nextOffset := offset + int64(len(transactions))
+		response.NextOffset = &nextOffset
+	}
+
+	return response
+}
+
+// candidates calls fn for every indexed transaction that could match
+// the request, most recent first. The address and transaction hash
+// indexes are used when the conditions are combined with AND.
+func (i *Indexer) candidates(
+	request *types.SearchTransactionsRequest,
+	filter *searchFilter,
+	maxBlock uint64,
+	fn func(*types.BlockTransaction),
+) error {
+	visit := func(txKey []byte) error {
+		value, err := i.db.Get(txKey, nil)
+		if err != nil {
+			return err
+		}
+		var blockTransaction types.BlockTransaction
+		if err := json.Unmarshal(value, &blockTransaction); err != nil {
+			return err
+		}
+		if uint64(blockTransaction.BlockIdentifier.Index) <= maxBlock {
+			fn(&blockTransaction)
+		}
+		return nil
+	}
+
+	if !filter.or && request.TransactionIdentifier != nil {
+		txKey, err := i.db.Get(hashKey(request.TransactionIdentifier.Hash), nil)
+		if err == leveldb.ErrNotFound {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		return visit(txKey)
+	}
+
+	if !filter.or && filter.address != nil {
+		address, err := viteTypes.HexToAddress(*filter.address)
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrSearchRequestInvalid, err.Error())
+		}
+
+		prefix := append(append([]byte{}, addressKeyPrefix...), address.Bytes()...)
+		limit := append(append([]byte{}, prefix...), transactionKey(maxBlock+1, 0)...)
+		iter := i.db.NewIterator(&util.Range{Start: prefix, Limit: limit}, nil)
+		defer iter.Release()
+		for ok := iter.Last(); ok; ok = iter.Prev() {
+			if err := visit(iter.Key()[len(prefix):]); err != nil {
+				return err
+			}
+		}
+		return iter.Error()
+	}
+
+	iter := i.db.NewIterator(&util.Range{
+		Start: transactionKeyPrefix,
+		Limit: transactionKey(maxBlock+1, 0),
+	}, nil)
+	defer iter.Release()
+	for ok := iter.Last(); ok; ok = iter.Prev() {
+		var blockTransaction types.BlockTransaction
+		if err := json.Unmarshal(iter.Value(), &blockTransaction); err != nil {
+			return err
+		}
+		fn(&blockTransaction)
+	}
+	return iter.Error()
+}
+
+// searchFilter contains the conditions of a search request.
+type searchFilter struct {
+	or bool
+
+	hash     *string
+	address  *string
+	opType   *string
+	status   *string
+	currency *types.Currency
+	success  *bool
+}
+
+func newSearchFilter(request *types.SearchTransactionsRequest) (*searchFilter, error) {
+	filter := &searchFilter{
+		opType:   request.Type,
+		status:   request.Status,
+		currency: request.Currency,
+		success:  request.Success,
+	}
+
+	if request.Operator != nil && *request.Operator == types.OR {
+		filter.or = true
+	}
+
+	if request.TransactionIdentifier != nil {
+		filter.hash = &request.TransactionIdentifier.Hash
+	}
+
+	if request.Address != nil {
+		filter.address = request.Address
+	}
+	if request.AccountIdentifier != nil {
+		if filter.address != nil && *filter.address != request.AccountIdentifier.Address {
+			return nil, fmt.Errorf("%w: address and account identifier do not match", ErrSearchRequestInvalid)
+		}
+		filter.address = &request.AccountIdentifier.Address
+	}
+
+	return filter, nil
+}
+
+// match checks if a transaction satisfies the filter. With AND all
+// operation conditions must be satisfied by the same operation.
+func (f *searchFilter) match(transaction *types.Transaction) bool {
+	if f.hash != nil {
+		hashMatch := *f.hash == transaction.TransactionIdentifier.Hash
+		if f.or && hashMatch {
+			return true
+		}
+		if !f.or && !hashMatch {
+			return false
+		}
+	}
+
+	if !f.or && !f.hasOperationConditions() {
+		return true
+	}
+
+	for _, op := range transaction.Operations {
+		if f.matchOperation(op) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (f *searchFilter) hasOperationConditions() bool {
+	return f.address != nil ||
+		f.opType != nil ||
+		f.status != nil ||
+		f.currency != nil ||
+		f.success != nil
+}
+
+func (f *searchFilter) matchOperation(op *types.Operation) bool {
+	conditions := []bool{}
+	if f.address != nil {
+		conditions = append(conditions, op.Account != nil && op.Account.Address == *f.address)
+	}
+	if f.opType != nil {
+		conditions = append(conditions, op.Type == *f.opType)
+	}
+	if f.status != nil {
+		conditions = append(conditions, op.Status != nil && *op.Status == *f.status)
+	}
+	if f.currency != nil {
+		conditions = append(conditions, op.Amount != nil && currencyMatches(op.Amount.Currency, f.currency))
+	}
+	if f.success != nil {
+		conditions = append(conditions, op.Status != nil && statusSuccessful(*op.Status) == *f.success)
+	}
+
+	for _, condition := range conditions {
+		if f.or && condition {
+			return true
+		}
+		if !f.or && !condition {
+			return false
+		}
+	}
+
+	return !f.or
+}
+
+// currencyMatches compares currencies by tti when present,
+// otherwise by symbol and decimals.
+func currencyMatches(currency *types.Currency, filter *types.Currency) bool {
+	if currency == nil {
+		return false
+	}
+	if filterTti, ok := filter.Metadata["tti"]; ok {
+		return currency.Metadata["tti"] == filterTti
+	}
+	return currency.Symbol == filter.Symbol && currency.Decimals == filter.Decimals
+}
+
+// statusSuccessful returns if an operation status is successful.
+func statusSuccessful(status string) bool {
+	for _, operationStatus := range OperationStatuses {
+		if operationStatus.Status == status {
+			return operationStatus.Successful
+		}
+	}
+	return false
+}
+
+// transactionAddresses returns the distinct addresses
+// of all operations in a transaction.
+func transactionAddresses(transaction *types.Transaction) []viteTypes.Address {
+	addresses := []viteTypes.Address{}
+	seen := map[viteTypes.Address]bool{}
+	for _, op := range transaction.Operations {
+		if op.Account == nil {
+			continue
+		}
+		address, err := viteTypes.HexToAddress(op.Account.Address)
+		if err != nil || seen[address] {
+			continue
+		}
+		seen[address] = true
+		addresses = append(addresses, address)
+	}
+	return addresses
+}
+
+func blockTransactionsPrefix(height uint64) []byte {
+	return append(append([]byte{}, transactionKeyPrefix...), encodeUint64(height)...)
+}
+
+func transactionKey(height uint64, index uint32) []byte {
+	key := blockTransactionsPrefix(height)
+	indexBytes := make([]byte, 4)
+	binary.BigEndian.PutUint32(indexBytes, index)
+	return append(key, indexBytes...)
+}
+
+func hashKey(hash string) []byte {
+	return append(append([]byte{}, hashKeyPrefix...), []byte(hash)...)
+}
+
+func addressKey(address viteTypes.Address, txKey []byte) []byte {
+	key := append(append([]byte{}, addressKeyPrefix...), address.Bytes()...)
+	return append(key, txKey...)
+}
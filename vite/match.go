@@ -1,8 +1,10 @@
 package vite
 
 import (
+	"encoding/hex"
 	"fmt"
 	"reflect"
+	"strings"
 
 	"github.com/azbuky/rosetta-vite/utils"
 	"github.com/coinbase/rosetta-sdk-go/parser"
@@ -20,6 +22,11 @@ func MatchTransaction(operations []*types.Operation) (*TransactionDescription, e
 		return description, nil
 	}
 
+	description, err = MatchCreateContractTransaction(operations)
+	if err == nil {
+		return description, nil
+	}
+
 	description, err = MatchResponseTransaction(operations)
 	if err == nil {
 		return description, nil
@@ -28,24 +35,13 @@ func MatchTransaction(operations []*types.Operation) (*TransactionDescription, e
 	return nil, fmt.Errorf("could not match operations")
 }
 
+// MatchRequestTransaction matches a REQUEST operation
+// with an optional FEE operation paid by the same account.
 func MatchRequestTransaction(operations []*types.Operation) (*TransactionDescription, error) {
-	if len(operations) != 1 {
-		return nil, fmt.Errorf("incorrect number of ops")
-	}
-
-	reqOp := operations[0]
-
-	if err := CheckRequestOpType(reqOp); err != nil {
-		return nil, err
-	}
-
-	// convert amount to positive value
-	amount := *reqOp.Amount
-	value, err := types.NegateValue(amount.Value)
+	reqOp, fee, err := matchSendOperations(operations, requestOperationDescription())
 	if err != nil {
 		return nil, err
 	}
-	amount.Value = value
 
 	metadata := RequestOperationMetadata{}
 	err = utils.UnmarshalJSONMap(reqOp.Metadata, &metadata)
@@ -57,15 +53,138 @@ func MatchRequestTransaction(operations []*types.Operation) (*TransactionDescrip
 		Address: metadata.ToAddress,
 	}
 
-	transaction := &TransactionDescription{
+	return &TransactionDescription{
 		OperationType: RequestOpType,
 		Account:       *reqOp.Account,
 		FromAccount:   reqOp.Account,
 		ToAccount:     toAccount,
-		Amount:        amount,
+		Amount:        *reqOp.Amount,
+		Fee:           fee,
 		Data:          metadata.Data,
+	}, nil
+}
+
+// MatchCreateContractTransaction matches a CREATE_CONTRACT operation
+// with an optional FEE operation paid by the same account.
+func MatchCreateContractTransaction(operations []*types.Operation) (*TransactionDescription, error) {
+	createOp, fee, err := matchSendOperations(operations, createContractOperationDescription())
+	if err != nil {
+		return nil, err
 	}
-	return transaction, nil
+
+	metadata := CreateContractOperationMetadata{}
+	if err := utils.UnmarshalJSONMap(createOp.Metadata, &metadata); err != nil {
+		return nil, err
+	}
+
+	bytecode, err := hex.DecodeString(strings.TrimPrefix(metadata.Bytecode, "0x"))
+	if err != nil || len(bytecode) == 0 {
+		return nil, fmt.Errorf("%s is not a valid bytecode", metadata.Bytecode)
+	}
+
+	gid := viteTypes.DELEGATE_GID
+	if len(metadata.Gid) > 0 {
+		gid, err = viteTypes.HexToGid(metadata.Gid)
+		if err != nil {
+			return nil, fmt.Errorf("%s is not a valid gid", metadata.Gid)
+		}
+	}
+
+	quotaMultiplier := metadata.QuotaMultiplier
+	if quotaMultiplier == 0 {
+		quotaMultiplier = DefaultQuotaMultiplier
+	}
+	if quotaMultiplier < MinQuotaMultiplier || quotaMultiplier > MaxQuotaMultiplier {
+		return nil, fmt.Errorf(
+			"quota multiplier must be between %d and %d",
+			MinQuotaMultiplier,
+			MaxQuotaMultiplier,
+		)
+	}
+	if metadata.ConfirmTimes > MaxConfirmTimes {
+		return nil, fmt.Errorf("confirm times must not exceed %d", MaxConfirmTimes)
+	}
+	if metadata.SeedCount > metadata.ConfirmTimes {
+		return nil, fmt.Errorf("seed count must not exceed confirm times")
+	}
+
+	return &TransactionDescription{
+		OperationType: CreateContractOpType,
+		Account:       *createOp.Account,
+		FromAccount:   createOp.Account,
+		Amount:        *createOp.Amount,
+		Fee:           fee,
+		Data:          bytecode,
+		Contract: &ContractDescription{
+			Gid:             gid,
+			ConfirmTimes:    metadata.ConfirmTimes,
+			SeedCount:       metadata.SeedCount,
+			QuotaMultiplier: quotaMultiplier,
+		},
+	}, nil
+}
+
+// matchSendOperations matches a send operation with an optional FEE
+// operation paid by the same account. The amount and fee of the
+// returned operation are converted to positive values.
+func matchSendOperations(
+	operations []*types.Operation,
+	sendDescription *parser.OperationDescription,
+) (*types.Operation, *types.Amount, error) {
+	if len(operations) > 2 {
+		return nil, nil, fmt.Errorf("incorrect number of ops")
+	}
+
+	description := &parser.Descriptions{
+		OperationDescriptions: []*parser.OperationDescription{
+			sendDescription,
+			feeOperationDescription(true),
+		},
+		ErrUnmatched: true,
+	}
+
+	matches, err := parser.MatchOperations(description, operations)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := ValidateMatch(matches[0]); err != nil {
+		return nil, nil, err
+	}
+
+	op, _ := matches[0].First()
+	sendOp := *op
+
+	// convert amount to positive value
+	amount := *sendOp.Amount
+	value, err := types.NegateValue(amount.Value)
+	if err != nil {
+		return nil, nil, err
+	}
+	amount.Value = value
+	sendOp.Amount = &amount
+
+	if matches[1] == nil {
+		return &sendOp, nil, nil
+	}
+
+	feeOp, _ := matches[1].First()
+	if feeOp.Account.Address != sendOp.Account.Address {
+		return nil, nil, fmt.Errorf("fee must be paid by the %s account", sendOp.Type)
+	}
+	if types.Hash(feeOp.Amount.Currency) != types.Hash(sendOp.Amount.Currency) {
+		return nil, nil, fmt.Errorf("fee currency must match the %s currency", sendOp.Type)
+	}
+
+	// convert fee to positive value
+	fee := *feeOp.Amount
+	feeValue, err := types.NegateValue(fee.Value)
+	if err != nil {
+		return nil, nil, err
+	}
+	fee.Value = feeValue
+
+	return &sendOp, &fee, nil
 }
 
 func MatchResponseTransaction(operations []*types.Operation) (*TransactionDescription, error) {
@@ -100,25 +219,62 @@ func MatchResponseTransaction(operations []*types.Operation) (*TransactionDescri
 	return transaction, nil
 }
 
+func requestOperationDescription() *parser.OperationDescription {
+	return &parser.OperationDescription{
+		Type: RequestOpType,
+		Account: &parser.AccountDescription{
+			Exists: true,
+		},
+		Amount: &parser.AmountDescription{
+			Exists: true,
+			Sign:   parser.NegativeOrZeroAmountSign,
+		},
+		Metadata: []*parser.MetadataDescription{
+			{
+				Key:       MetadataToAddressKey,
+				ValueKind: reflect.String,
+			},
+		},
+	}
+}
+
+func createContractOperationDescription() *parser.OperationDescription {
+	return &parser.OperationDescription{
+		Type: CreateContractOpType,
+		Account: &parser.AccountDescription{
+			Exists: true,
+		},
+		Amount: &parser.AmountDescription{
+			Exists: true,
+			Sign:   parser.NegativeOrZeroAmountSign,
+		},
+		Metadata: []*parser.MetadataDescription{
+			{
+				Key:       MetadataBytecodeKey,
+				ValueKind: reflect.String,
+			},
+		},
+	}
+}
+
+func feeOperationDescription(optional bool) *parser.OperationDescription {
+	return &parser.OperationDescription{
+		Type: FeeOpType,
+		Account: &parser.AccountDescription{
+			Exists: true,
+		},
+		Amount: &parser.AmountDescription{
+			Exists: true,
+			Sign:   parser.NegativeAmountSign,
+		},
+		Optional: optional,
+	}
+}
+
 func CheckRequestOpType(operation *types.Operation) error {
 	description := &parser.Descriptions{
 		OperationDescriptions: []*parser.OperationDescription{
-			{
-				Type: RequestOpType,
-				Account: &parser.AccountDescription{
-					Exists: true,
-				},
-				Amount: &parser.AmountDescription{
-					Exists: true,
-					Sign:   parser.NegativeOrZeroAmountSign,
-				},
-				Metadata: []*parser.MetadataDescription{
-					{
-						Key:       MetadataToAddressKey,
-						ValueKind: reflect.String,
-					},
-				},
-			},
+			requestOperationDescription(),
 		},
 		ErrUnmatched: true,
 	}
@@ -212,16 +368,7 @@ func CheckBurnOpType(operation *types.Operation) error {
 func CheckFeeOpType(operation *types.Operation) error {
 	description := &parser.Descriptions{
 		OperationDescriptions: []*parser.OperationDescription{
-			{
-				Type: FeeOpType,
-				Account: &parser.AccountDescription{
-					Exists: true,
-				},
-				Amount: &parser.AmountDescription{
-					Exists: true,
-					Sign:   parser.NegativeAmountSign,
-				},
-			},
+			feeOperationDescription(false),
 		},
 		ErrUnmatched: true,
 	}
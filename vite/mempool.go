@@ -0,0 +1,147 @@
+package vite
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+
+	viteTypes "github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/ledger"
+	"github.com/vitelabs/go-vite/rpcapi/api"
+)
+
+const (
+	// unreceivedBlocksPageSize is the number of unreceived
+	// blocks requested from gvite in a single call.
+	unreceivedBlocksPageSize = uint64(100)
+)
+
+// Mempool returns the identifiers of all pending send blocks.
+// A send block is pending until its receive block is created,
+// both the unconfirmed blocks of gvite and the unreceived blocks
+// of the watched addresses are reported.
+func (ec *Client) Mempool(
+	ctx context.Context,
+) ([]*types.TransactionIdentifier, error) {
+	seen := map[viteTypes.Hash]bool{}
+	txIds := []*types.TransactionIdentifier{}
+
+	addTransaction := func(hash viteTypes.Hash) {
+		if seen[hash] {
+			return
+		}
+		seen[hash] = true
+		txIds = append(txIds, &types.TransactionIdentifier{
+			Hash: hash.Hex(),
+		})
+	}
+
+	unconfirmedBlocks, err := ec.c.GetAllUnconfirmedBlocks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// receive blocks of unconfirmed send blocks are unconfirmed as well
+	received := map[viteTypes.Hash]viteTypes.Hash{}
+	for _, block := range unconfirmedBlocks {
+		if block != nil && ledger.IsReceiveBlock(block.BlockType) {
+			received[block.FromBlockHash] = block.Hash
+		}
+	}
+	for _, block := range unconfirmedBlocks {
+		if block == nil {
+			continue
+		}
+		var receiveBlockHash *viteTypes.Hash
+		if hash, ok := received[block.Hash]; ok {
+			receiveBlockHash = &hash
+		}
+		if isPendingSendBlock(block.BlockType, receiveBlockHash) {
+			addTransaction(block.Hash)
+		}
+	}
+
+	for _, address := range ec.mempoolAddresses {
+		blocks, err := ec.unreceivedBlocks(ctx, address, 0)
+		if err != nil {
+			return nil, err
+		}
+		for _, block := range blocks {
+			if isPendingSendBlock(block.BlockType, block.ReceiveBlockHash) {
+				addTransaction(block.Hash)
+			}
+		}
+	}
+
+	return txIds, nil
+}
+
+// MempoolTransaction returns the pending send block with the given
+// transaction identifier, with all operations in IntentStatus.
+func (ec *Client) MempoolTransaction(
+	ctx context.Context,
+	transactionIdentifier *types.TransactionIdentifier,
+) (*types.Transaction, error) {
+	hash, err := viteTypes.HexToHash(transactionIdentifier.Hash)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrTransactionNotFound, err.Error())
+	}
+
+	accountBlock, err := ec.c.GetAccountBlockByHash(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	if accountBlock == nil || accountBlock.Hash.IsZero() {
+		return nil, fmt.Errorf("%w: %s does not exist", ErrTransactionNotFound, hash)
+	}
+	if !isPendingSendBlock(accountBlock.BlockType, accountBlock.ReceiveBlockHash) {
+		return nil, fmt.Errorf("%w: %s is not pending", ErrTransactionNotFound, hash)
+	}
+	if err := ec.tokens.annotate(ctx, accountBlock); err != nil {
+		return nil, err
+	}
+
+	transaction, err := AccountBlockToTransaction(accountBlock, true)
+	if err != nil {
+		return nil, err
+	}
+	for _, op := range transaction.Operations {
+		op.Status = StatusRef(IntentStatus, true)
+	}
+
+	return transaction, nil
+}
+
+// isPendingSendBlock returns true for a send block
+// for which no receive block was created yet.
+func isPendingSendBlock(blockType byte, receiveBlockHash *viteTypes.Hash) bool {
+	return ledger.IsSendBlock(blockType) && receiveBlockHash == nil
+}
+
+// unreceivedBlocks pages through the unreceived blocks of an address,
+// oldest first. Paging stops after limit blocks if limit is positive.
+func (ec *Client) unreceivedBlocks(
+	ctx context.Context,
+	address viteTypes.Address,
+	limit int64,
+) ([]*api.AccountBlock, error) {
+	pageSize := unreceivedBlocksPageSize
+	if limit > 0 && uint64(limit) < pageSize {
+		pageSize = uint64(limit)
+	}
+
+	result := []*api.AccountBlock{}
+	for page := uint64(0); ; page++ {
+		blocks, err := ec.c.GetUnreceivedBlocksByAddress(ctx, address, page, pageSize)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, blocks...)
+		if limit > 0 && int64(len(result)) >= limit {
+			return result[:limit], nil
+		}
+		if uint64(len(blocks)) < pageSize {
+			return result, nil
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package vite
+
+import (
+	"context"
+	"testing"
+
+	"github.com/azbuky/rosetta-vite/vite/rpc"
+	viteTypes "github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/rpcapi/api"
+)
+
+// testUnreceivedBlocks serves count unreceived blocks
+// and records the pages requested.
+type testUnreceivedBlocks struct {
+	rpc.RpcClient
+
+	count int
+	pages []uint64
+}
+
+func (c *testUnreceivedBlocks) GetUnreceivedBlocksByAddress(
+	ctx context.Context,
+	address viteTypes.Address,
+	page uint64,
+	pageSize uint64,
+) ([]*api.AccountBlock, error) {
+	c.pages = append(c.pages, page)
+
+	blocks := []*api.AccountBlock{}
+	for i := page * pageSize; i < (page+1)*pageSize && i < uint64(c.count); i++ {
+		blocks = append(blocks, &api.AccountBlock{})
+	}
+	return blocks, nil
+}
+
+func TestUnreceivedBlocksLimit(t *testing.T) {
+	tests := []struct {
+		name     string
+		count    int
+		limit    int64
+		expected int
+		pages    int
+	}{
+		{name: "no blocks", count: 0, limit: 0, expected: 0, pages: 1},
+		{name: "all blocks", count: 250, limit: 0, expected: 250, pages: 3},
+		{name: "full last page", count: 200, limit: 0, expected: 200, pages: 3},
+		{name: "limit below page size", count: 250, limit: 5, expected: 5, pages: 1},
+		{name: "limit of a page", count: 250, limit: 100, expected: 100, pages: 1},
+		{name: "limit above page size", count: 250, limit: 150, expected: 150, pages: 2},
+		{name: "limit above count", count: 20, limit: 50, expected: 20, pages: 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := &testUnreceivedBlocks{count: test.count}
+			client := &Client{c: c}
+
+			blocks, err := client.unreceivedBlocks(context.Background(), viteTypes.Address{}, test.limit)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(blocks) != test.expected {
+				t.Fatalf("expected %d blocks, got %d", test.expected, len(blocks))
+			}
+			if len(c.pages) != test.pages {
+				t.Fatalf("expected %d pages requested, got %d", test.pages, len(c.pages))
+			}
+		})
+	}
+}
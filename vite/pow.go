@@ -0,0 +1,187 @@
+package vite
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"log"
+	"math/big"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/azbuky/rosetta-vite/metrics"
+	"github.com/vitelabs/go-vite/common/helper"
+	viteTypes "github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/crypto"
+	"github.com/vitelabs/go-vite/pow"
+	"golang.org/x/crypto/blake2b"
+)
+
+const (
+	// powCheckInterval is the number of nonces a solver
+	// tries before checking if the search was canceled.
+	powCheckInterval = 1 << 12
+
+	// DefaultLocalPoWTimeout is the time the local solver searches
+	// a nonce before falling back to gvite util_getPoWNonce.
+	DefaultLocalPoWTimeout = 30 * time.Second
+)
+
+// PoWSolver selects how PoW nonces are computed.
+type PoWSolver string
+
+const (
+	// LocalPoWSolver computes nonces in process.
+	LocalPoWSolver PoWSolver = "LOCAL"
+
+	// RpcPoWSolver computes nonces with gvite util_getPoWNonce.
+	RpcPoWSolver PoWSolver = "RPC"
+)
+
+// SolvePoW searches a nonce such that blake2b(nonce || dataHash)
+// satisfies difficulty, using threads goroutines. Each goroutine
+// scans its own range starting at a random nonce. The search stops
+// when the context is canceled or its deadline is exceeded.
+func SolvePoW(
+	ctx context.Context,
+	difficulty *big.Int,
+	dataHash viteTypes.Hash,
+	threads int,
+) ([]byte, error) {
+	if difficulty == nil || difficulty.Sign() <= 0 {
+		return nil, fmt.Errorf("invalid difficulty %v", difficulty)
+	}
+	target := pow.DifficultyToTarget(difficulty)
+	if target == nil || target.BitLen() > 256 {
+		return nil, fmt.Errorf("invalid difficulty %s", difficulty.String())
+	}
+	target256 := helper.LeftPadBytes(target.Bytes(), 32)
+
+	if threads <= 0 {
+		threads = runtime.NumCPU()
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	start := binary.BigEndian.Uint64(crypto.GetEntropyCSPRNG(8))
+	step := ^uint64(0) / uint64(threads)
+
+	result := make(chan []byte, threads)
+	var wg sync.WaitGroup
+	for i := 0; i < threads; i++ {
+		wg.Add(1)
+		go func(from uint64) {
+			defer wg.Done()
+			if nonce := solvePoWRange(ctx, target256, dataHash.Bytes(), from); nonce != nil {
+				result <- nonce
+			}
+		}(start + uint64(i)*step)
+	}
+
+	go func() {
+		wg.Wait()
+		close(result)
+	}()
+
+	select {
+	case nonce, ok := <-result:
+		if ok {
+			return nonce, nil
+		}
+	case <-ctx.Done():
+	}
+
+	return nil, fmt.Errorf("%w: unable to solve pow", ctx.Err())
+}
+
+// solvePoWRange increments the nonce starting at from until it satisfies
+// target, and returns nil if the context is canceled first.
+func solvePoWRange(ctx context.Context, target256 []byte, data []byte, from uint64) []byte {
+	hash, _ := blake2b.New256(nil)
+	nonce := make([]byte, 8)
+	out := make([]byte, 0, blake2b.Size256)
+	for i := uint64(0); ; i++ {
+		if i%powCheckInterval == 0 && ctx.Err() != nil {
+			return nil
+		}
+
+		binary.LittleEndian.PutUint64(nonce, from+i)
+		hash.Reset()
+		hash.Write(nonce)
+		hash.Write(data)
+		out = hash.Sum(out[:0])
+		if pow.QuickGreater(out, target256) {
+			return nonce
+		}
+	}
+}
+
+// powNonce returns the base64 encoded nonce for difficulty and
+// dataHash using the configured solver. If the local solver fails or
+// times out the nonce is requested from gvite instead, unless the
+// caller canceled the request.
+func (ec *Client) powNonce(
+	ctx context.Context,
+	difficulty string,
+	dataHash viteTypes.Hash,
+) (string, error) {
+	if ec.powSolver == RpcPoWSolver {
+		return ec.rpcPoWNonce(ctx, difficulty, dataHash)
+	}
+
+	nonce, err := ec.localPoWNonce(ctx, difficulty, dataHash)
+	if err == nil || ctx.Err() != nil {
+		return nonce, err
+	}
+
+	log.Printf("local pow: %s, falling back to gvite", err.Error())
+	return ec.rpcPoWNonce(ctx, difficulty, dataHash)
+}
+
+// localPoWNonce solves a nonce in process within localPoWTimeout.
+func (ec *Client) localPoWNonce(
+	ctx context.Context,
+	difficulty string,
+	dataHash viteTypes.Hash,
+) (nonce string, err error) {
+	start := time.Now()
+	defer func() {
+		metrics.ObservePoW(string(LocalPoWSolver), start, err)
+	}()
+
+	difficultyValue, ok := new(big.Int).SetString(difficulty, 10)
+	if !ok {
+		return "", fmt.Errorf("%s is not a valid difficulty", difficulty)
+	}
+
+	timeout := ec.localPoWTimeout
+	if timeout <= 0 {
+		timeout = DefaultLocalPoWTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	nonceBytes, err := SolvePoW(ctx, difficultyValue, dataHash, ec.powThreads)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(nonceBytes), nil
+}
+
+// rpcPoWNonce requests a nonce from gvite util_getPoWNonce.
+func (ec *Client) rpcPoWNonce(
+	ctx context.Context,
+	difficulty string,
+	dataHash viteTypes.Hash,
+) (nonce string, err error) {
+	start := time.Now()
+	defer func() {
+		metrics.ObservePoW(string(RpcPoWSolver), start, err)
+	}()
+
+	return ec.c.GetPoWNonce(ctx, difficulty, dataHash.Hex())
+}
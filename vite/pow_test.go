@@ -0,0 +1,143 @@
+package vite
+
+import (
+	"context"
+	"encoding/base64"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/azbuky/rosetta-vite/vite/rpc"
+	viteTypes "github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/crypto"
+	"github.com/vitelabs/go-vite/pow"
+)
+
+func TestSolvePoW(t *testing.T) {
+	tests := []struct {
+		name       string
+		difficulty *big.Int
+		threads    int
+	}{
+		{name: "single thread", difficulty: big.NewInt(1), threads: 1},
+		{name: "low difficulty", difficulty: big.NewInt(10000), threads: 2},
+		{name: "higher difficulty", difficulty: big.NewInt(1000000), threads: 4},
+		{name: "default threads", difficulty: big.NewInt(100000), threads: 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dataHash, err := viteTypes.BytesToHash(crypto.Hash256([]byte(test.name)))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+			defer cancel()
+
+			nonce, err := SolvePoW(ctx, test.difficulty, dataHash, test.threads)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !pow.CheckPowNonce(test.difficulty, nonce, dataHash.Bytes()) {
+				t.Fatalf("nonce %x does not satisfy difficulty %s", nonce, test.difficulty)
+			}
+		})
+	}
+}
+
+func TestSolvePoWErrors(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		difficulty *big.Int
+	}{
+		{name: "nil difficulty", ctx: context.Background(), difficulty: nil},
+		{name: "zero difficulty", ctx: context.Background(), difficulty: big.NewInt(0)},
+		{name: "negative difficulty", ctx: context.Background(), difficulty: big.NewInt(-1)},
+		{name: "canceled", ctx: canceled, difficulty: new(big.Int).Lsh(big.NewInt(1), 60)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			nonce, err := SolvePoW(test.ctx, test.difficulty, viteTypes.Hash{}, 1)
+			if err == nil {
+				t.Fatalf("expected an error, got nonce %x", nonce)
+			}
+		})
+	}
+}
+
+// testPoWNonce answers util_getPoWNonce calls with a fixed nonce.
+type testPoWNonce struct {
+	rpc.RpcClient
+
+	calls int
+}
+
+func (c *testPoWNonce) GetPoWNonce(ctx context.Context, difficulty string, hash string) (string, error) {
+	c.calls++
+	if ctx.Err() != nil {
+		return "", ctx.Err()
+	}
+	return "cnBjLW5vbmNl", nil
+}
+
+func TestPowNonceFallback(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name       string
+		solver     PoWSolver
+		ctx        context.Context
+		difficulty string
+		rpcCalls   int
+		err        bool
+	}{
+		{name: "local", solver: LocalPoWSolver, ctx: context.Background(), difficulty: "10000", rpcCalls: 0},
+		{name: "rpc", solver: RpcPoWSolver, ctx: context.Background(), difficulty: "10000", rpcCalls: 1},
+		{name: "local timeout", solver: LocalPoWSolver, ctx: context.Background(), difficulty: "1152921504606846976", rpcCalls: 1},
+		{name: "local failure", solver: LocalPoWSolver, ctx: context.Background(), difficulty: "0", rpcCalls: 1},
+		{name: "canceled", solver: LocalPoWSolver, ctx: canceled, difficulty: "1152921504606846976", rpcCalls: 0, err: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := &testPoWNonce{}
+			client := &Client{
+				c:               c,
+				powSolver:       test.solver,
+				powThreads:      1,
+				localPoWTimeout: 50 * time.Millisecond,
+			}
+
+			nonce, err := client.powNonce(test.ctx, test.difficulty, viteTypes.Hash{})
+			if test.err {
+				if err == nil {
+					t.Fatalf("expected an error, got nonce %s", nonce)
+				}
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			if c.calls != test.rpcCalls {
+				t.Fatalf("expected %d rpc calls, got %d", test.rpcCalls, c.calls)
+			}
+			if test.err || test.rpcCalls > 0 {
+				return
+			}
+
+			nonceBytes, err := base64.StdEncoding.DecodeString(nonce)
+			if err != nil {
+				t.Fatal(err)
+			}
+			difficulty, _ := new(big.Int).SetString(test.difficulty, 10)
+			if !pow.CheckPowNonce(difficulty, nonceBytes, viteTypes.Hash{}.Bytes()) {
+				t.Fatalf("nonce %s does not satisfy difficulty %s", nonce, test.difficulty)
+			}
+		})
+	}
+}
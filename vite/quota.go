@@ -0,0 +1,82 @@
+package vite
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	viteTypes "github.com/vitelabs/go-vite/common/types"
+)
+
+const (
+	// UsePowAuto lets ConstructionMetadata decide if PoW
+	// is needed from the available quota of the account.
+	UsePowAuto = "auto"
+
+	// Quota costs of the current gvite quota table
+	txQuota              = uint64(21000)
+	txDataQuota          = uint64(68)
+	createTxRequestQuota = uint64(31000)
+
+	// createContractDataPrefixSize is the size of the gid, contract
+	// type, confirm times, seed count and quota multiplier that
+	// prefix the bytecode of a create contract block.
+	createContractDataPrefixSize = viteTypes.GidSize + 4
+)
+
+// Defines the quota based PoW decision reported in metadata
+type PowDecision struct {
+	AvailableQuota uint64 `json:"availableQuota,string"`
+	RequiredQuota  uint64 `json:"requiredQuota,string"`
+	UsePow         bool   `json:"usePow"`
+}
+
+// EstimateQuota estimates the quota consumed by a block created for
+// the operation type with dataSize bytes of data. Calls to built-in
+// contracts consume additional quota which is not estimated.
+func EstimateQuota(operationType string, dataSize int) uint64 {
+	baseQuota := txQuota
+	if operationType == CreateContractOpType {
+		baseQuota = createTxRequestQuota
+	}
+	return baseQuota + uint64(dataSize)*txDataQuota
+}
+
+// powDecision compares the available quota of address with the quota
+// estimated for a block of the operation type with the final block data.
+func (ec *Client) powDecision(
+	ctx context.Context,
+	address viteTypes.Address,
+	operationType string,
+	data []byte,
+) (*PowDecision, error) {
+	availableQuota, err := ec.availableQuota(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+
+	return newPowDecision(availableQuota, EstimateQuota(operationType, len(data))), nil
+}
+
+// availableQuota returns the current quota of address.
+func (ec *Client) availableQuota(ctx context.Context, address viteTypes.Address) (uint64, error) {
+	quotaInfo, err := ec.c.GetQuotaByAccount(ctx, address)
+	if err != nil {
+		return 0, err
+	}
+
+	availableQuota, err := strconv.ParseUint(quotaInfo.CurrentQuota, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: invalid quota %s", err, quotaInfo.CurrentQuota)
+	}
+
+	return availableQuota, nil
+}
+
+func newPowDecision(availableQuota uint64, requiredQuota uint64) *PowDecision {
+	return &PowDecision{
+		AvailableQuota: availableQuota,
+		RequiredQuota:  requiredQuota,
+		UsePow:         availableQuota < requiredQuota,
+	}
+}
@@ -0,0 +1,180 @@
+package vite
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/azbuky/rosetta-vite/utils"
+	"github.com/coinbase/rosetta-sdk-go/types"
+
+	viteTypes "github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/ledger"
+	"github.com/vitelabs/go-vite/rpcapi/api"
+)
+
+// Defines a RESPONSE intent for an unreceived send block. AccountBlock
+// and PowDecision are only set when the receive blocks are chained for
+// a public key.
+type ReceiveTransaction struct {
+	Operations   []*types.Operation
+	AccountBlock *api.AccountBlock
+	PowDecision  *PowDecision
+}
+
+// ReceiveTransactions returns a RESPONSE intent for every unreceived
+// send block of account, oldest first, limited to limit intents if
+// limit is positive. If publicKey is provided the unsigned receive
+// blocks are created as well, each one chained to the previous one
+// starting at the latest account block.
+//
+// The quota of the account is spent by the chained blocks in order. The
+// first block gets a PoW nonce when the quota is insufficient. gvite only
+// computes the PoW difficulty of a block following the latest account
+// block, so blocks after the first one requiring PoW are returned with
+// their PoW decision but without an unsigned transaction, they can be
+// received once the previous blocks were submitted.
+func (ec *Client) ReceiveTransactions(
+	ctx context.Context,
+	account *types.AccountIdentifier,
+	publicKey *types.PublicKey,
+	limit int64,
+) ([]*ReceiveTransaction, error) {
+	address, err := viteTypes.HexToAddress(account.Address)
+	if err != nil {
+		return nil, fmt.Errorf("%s is not a valid address", account.Address)
+	}
+
+	sendBlocks, err := ec.unreceivedBlocks(ctx, address, limit)
+	if err != nil {
+		return nil, err
+	}
+	if err := ec.tokens.annotate(ctx, sendBlocks...); err != nil {
+		return nil, err
+	}
+
+	var metadata *ConstructionMetadata
+	var availableQuota uint64
+	if publicKey != nil {
+		if viteTypes.PubkeyToAddress(publicKey.Bytes) != address {
+			return nil, fmt.Errorf("public key does not match %s", account.Address)
+		}
+
+		latestBlock, err := ec.c.GetLatestAccountBlock(ctx, address)
+		if err != nil {
+			return nil, err
+		}
+
+		metadata = &ConstructionMetadata{
+			PreviousHash: latestBlock.Hash.Hex(),
+		}
+		if len(latestBlock.Height) > 0 {
+			metadata.Height, err = strconv.ParseUint(latestBlock.Height, 10, 64)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		availableQuota, err = ec.availableQuota(ctx, address)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	chained := publicKey != nil
+
+	transactions := []*ReceiveTransaction{}
+	for _, sendBlock := range sendBlocks {
+		op, err := receiveOperation(account, sendBlock)
+		if err != nil {
+			return nil, err
+		}
+		transaction := &ReceiveTransaction{
+			Operations: []*types.Operation{op},
+		}
+		transactions = append(transactions, transaction)
+
+		if publicKey == nil {
+			continue
+		}
+
+		description, err := MatchResponseTransaction(transaction.Operations)
+		if err != nil {
+			return nil, err
+		}
+
+		decision := newPowDecision(availableQuota, EstimateQuota(ResponseOpType, len(description.Data)))
+		transaction.PowDecision = decision
+		if !chained {
+			continue
+		}
+		if !decision.UsePow {
+			availableQuota -= decision.RequiredQuota
+		} else {
+			// only the block following the latest account block can be solved
+			if len(transactions) > 1 {
+				chained = false
+				continue
+			}
+			prevHash, err := viteTypes.HexToHash(metadata.PreviousHash)
+			if err != nil {
+				return nil, err
+			}
+			metadata.Difficulty, metadata.Nonce, err = ec.solvePoW(
+				ctx,
+				address,
+				prevHash,
+				ledger.BlockTypeReceive,
+				nil,
+				description.Data,
+			)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		accountBlock, err := CreateAccountBlock(description, metadata, publicKey)
+		if err != nil {
+			return nil, err
+		}
+
+		hash, err := accountBlock.ComputeHash()
+		if err != nil {
+			return nil, err
+		}
+		accountBlock.Hash = *hash
+		transaction.AccountBlock = accountBlock
+
+		// the next receive block follows this one
+		metadata = &ConstructionMetadata{
+			Height:       metadata.Height + 1,
+			PreviousHash: hash.Hex(),
+		}
+	}
+
+	return transactions, nil
+}
+
+// receiveOperation returns the RESPONSE operation
+// that receives sendBlock on account.
+func receiveOperation(
+	account *types.AccountIdentifier,
+	sendBlock *api.AccountBlock,
+) (*types.Operation, error) {
+	metadata, err := utils.MarshalJSONMap(ResponseOperationMetadata{
+		SendBlockHash: sendBlock.Hash.Hex(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.Operation{
+		OperationIdentifier: &types.OperationIdentifier{
+			Index: 0,
+		},
+		Type:     ResponseOpType,
+		Account:  account,
+		Amount:   AmountForAccountBlock(sendBlock, false),
+		Metadata: metadata,
+	}, nil
+}
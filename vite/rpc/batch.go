@@ -0,0 +1,99 @@
+package rpc
+
+import (
+	"context"
+
+	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/rpc"
+	"github.com/vitelabs/go-vite/rpcapi/api"
+)
+
+// maxBatchSize is the maximum number of calls
+// sent to gvite in a single batch request.
+const maxBatchSize = 100
+
+// BatchCall is a single call of a batch request. The result is
+// unmarshaled into Result, which must be a non-nil pointer. Error
+// is set if gvite returned an error for this call or if the result
+// could not be unmarshaled.
+type BatchCall struct {
+	Method string
+	Args   []interface{}
+	Result interface{}
+	Error  error
+}
+
+// BatchCallContext sends all calls in as few round trips as possible.
+// The returned error is only set if a request could not be sent,
+// errors of individual calls are set in their Error field.
+func (c rpcClient) BatchCallContext(ctx context.Context, calls []*BatchCall) error {
+	for start := 0; start < len(calls); start += maxBatchSize {
+		end := start + maxBatchSize
+		if end > len(calls) {
+			end = len(calls)
+		}
+
+		elems := make([]rpc.BatchElem, end-start)
+		for i, call := range calls[start:end] {
+			elems[i] = rpc.BatchElem{
+				Method: call.Method,
+				Args:   call.Args,
+				Result: call.Result,
+			}
+		}
+
+		if err := c.cc.BatchCallContext(ctx, elems); err != nil {
+			return err
+		}
+
+		for i, elem := range elems {
+			calls[start+i].Error = elem.Error
+		}
+	}
+
+	return nil
+}
+
+// NewGetTokenInfoByIdCall creates a batch call of contract_getTokenInfoById.
+func NewGetTokenInfoByIdCall(tokenId string) (*BatchCall, *api.RpcTokenInfo) {
+	tokenInfo := &api.RpcTokenInfo{}
+	return &BatchCall{
+		Method: "contract_getTokenInfoById",
+		Args:   []interface{}{tokenId},
+		Result: tokenInfo,
+	}, tokenInfo
+}
+
+// NewGetSnapshotBlockByHeightCall creates a batch
+// call of ledger_getSnapshotBlockByHeight.
+func NewGetSnapshotBlockByHeightCall(height uint64) (*BatchCall, *api.SnapshotBlock) {
+	block := &api.SnapshotBlock{}
+	return &BatchCall{
+		Method: "ledger_getSnapshotBlockByHeight",
+		Args:   []interface{}{height},
+		Result: block,
+	}, block
+}
+
+// NewGetSyncInfoCall creates a batch call of net_syncInfo.
+func NewGetSyncInfoCall() (*BatchCall, *api.SyncInfo) {
+	syncInfo := &api.SyncInfo{}
+	return &BatchCall{
+		Method: "net_syncInfo",
+		Result: syncInfo,
+	}, syncInfo
+}
+
+// NewGetAccountBlocksCall creates a batch call of ledger_getAccountBlocks.
+func NewGetAccountBlocksCall(
+	address types.Address,
+	hash *types.Hash,
+	count uint64,
+) (*BatchCall, *[]*api.AccountBlock) {
+	blocks := &[]*api.AccountBlock{}
+	return &BatchCall{
+		Method: "ledger_getAccountBlocks",
+		Args:   []interface{}{address, hash, nil, count},
+		Result: blocks,
+	}, blocks
+}
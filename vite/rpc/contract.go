@@ -3,20 +3,23 @@ package rpc
 import (
 	"context"
 
+	"github.com/vitelabs/go-vite/common/types"
 	"github.com/vitelabs/go-vite/rpc"
 	"github.com/vitelabs/go-vite/rpcapi/api"
 )
 
 type ContractApi interface {
 	GetTokenInfoById(ctx context.Context, tokenId string) (*api.RpcTokenInfo, error)
+	GetQuotaByAccount(ctx context.Context, address types.Address) (*api.QuotaInfo, error)
+	GetTokenInfoList(ctx context.Context, pageIndex uint64, pageSize uint64) (*api.TokenInfoList, error)
 }
 
 type contractApi struct {
-	cc *rpc.Client
+	cc *callClient
 }
 
 func NewContractApi(cc *rpc.Client) ContractApi {
-	return &contractApi{cc: cc}
+	return &contractApi{cc: newCallClient(cc)}
 }
 
 func (ci contractApi) GetTokenInfoById(ctx context.Context, tokenId string) (tokenInfo *api.RpcTokenInfo, err error) {
@@ -24,3 +27,15 @@ func (ci contractApi) GetTokenInfoById(ctx context.Context, tokenId string) (tok
 	err = ci.cc.CallContext(ctx, tokenInfo, "contract_getTokenInfoById", tokenId)
 	return
 }
+
+func (ci contractApi) GetQuotaByAccount(ctx context.Context, address types.Address) (quotaInfo *api.QuotaInfo, err error) {
+	quotaInfo = &api.QuotaInfo{}
+	err = ci.cc.CallContext(ctx, quotaInfo, "contract_getQuotaByAccount", address)
+	return
+}
+
+func (ci contractApi) GetTokenInfoList(ctx context.Context, pageIndex uint64, pageSize uint64) (tokenInfoList *api.TokenInfoList, err error) {
+	tokenInfoList = &api.TokenInfoList{}
+	err = ci.cc.CallContext(ctx, tokenInfoList, "contract_getTokenInfoList", pageIndex, pageSize)
+	return
+}
@@ -2,8 +2,10 @@ package rpc
 
 import (
 	"context"
+	"strconv"
 
 	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/ledger"
 	"github.com/vitelabs/go-vite/rpc"
 	"github.com/vitelabs/go-vite/rpcapi/api"
 )
@@ -18,10 +20,12 @@ type LedgerApi interface {
 
 	GetAccountBlockByHash(ctx context.Context, blockHash types.Hash) (*api.AccountBlock, error)
 	GetAccountBlocks(ctx context.Context, address types.Address, hash *types.Hash, count uint64) ([]*api.AccountBlock, error)
+	GetAccountBlockByHeight(ctx context.Context, address types.Address, height uint64) (*api.AccountBlock, error)
 	GetAccountInfoByAddress(ctx context.Context, address types.Address) (*api.AccountInfo, error)
 	GetConfirmedBalances(ctx context.Context, snapshotHash types.Hash, addrList []types.Address, tokenIds []types.TokenTypeId) (result *api.GetBalancesRes, err error)
 	GetLatestAccountBlock(ctx context.Context, address types.Address) (*api.AccountBlock, error)
 	GetUnreceivedBlocksByAddress(ctx context.Context, address types.Address, page uint64, pageSize uint64) ([]*api.AccountBlock, error)
+	GetAllUnconfirmedBlocks(ctx context.Context) ([]*ledger.AccountBlock, error)
 
 	GetPoWDifficulty(ctx context.Context, param *api.GetPoWDifficultyParam) (*api.GetPoWDifficultyResult, error)
 
@@ -29,11 +33,11 @@ type LedgerApi interface {
 }
 
 type ledgerApi struct {
-	cc *rpc.Client
+	cc *callClient
 }
 
 func NewLedgerApi(cc *rpc.Client) LedgerApi {
-	return &ledgerApi{cc: cc}
+	return &ledgerApi{cc: newCallClient(cc)}
 }
 
 func (li ledgerApi) GetAccountBlockByHash(
@@ -52,7 +56,17 @@ func (li ledgerApi) GetAccountBlocks(
 	count uint64,
 ) (blocks []*api.AccountBlock, err error) {
 	blocks = []*api.AccountBlock{}
-	err = li.cc.CallContext(ctx, blocks, "ledger_getAccountBlocks", address, hash, nil, count)
+	err = li.cc.CallContext(ctx, &blocks, "ledger_getAccountBlocks", address, hash, nil, count)
+	return
+}
+
+func (li ledgerApi) GetAccountBlockByHeight(
+	ctx context.Context,
+	address types.Address,
+	height uint64,
+) (block *api.AccountBlock, err error) {
+	block = &api.AccountBlock{}
+	err = li.cc.CallContext(ctx, block, "ledger_getAccountBlockByHeight", address, strconv.FormatUint(height, 10))
 	return
 }
 
@@ -91,6 +105,14 @@ func (li ledgerApi) GetUnreceivedBlocksByAddress(
 	return
 }
 
+func (li ledgerApi) GetAllUnconfirmedBlocks(
+	ctx context.Context,
+) (result []*ledger.AccountBlock, err error) {
+	result = []*ledger.AccountBlock{}
+	err = li.cc.CallContext(ctx, &result, "ledger_getAllUnconfirmedBlocks")
+	return
+}
+
 func (li ledgerApi) GetPoWDifficulty(
 	ctx context.Context,
 	param *api.GetPoWDifficultyParam,
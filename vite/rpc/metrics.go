@@ -0,0 +1,50 @@
+package rpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/azbuky/rosetta-vite/metrics"
+	"github.com/vitelabs/go-vite/rpc"
+)
+
+// callClient wraps a gvite rpc.Client to record
+// the latency and failures of every call.
+type callClient struct {
+	*rpc.Client
+}
+
+func newCallClient(cc *rpc.Client) *callClient {
+	return &callClient{Client: cc}
+}
+
+func (c *callClient) Call(result interface{}, method string, args ...interface{}) error {
+	return c.CallContext(context.Background(), result, method, args...)
+}
+
+func (c *callClient) CallContext(
+	ctx context.Context,
+	result interface{},
+	method string,
+	args ...interface{},
+) error {
+	start := time.Now()
+	err := c.Client.CallContext(ctx, result, method, args...)
+	metrics.ObserveRPCCall(method, start, err)
+	return err
+}
+
+func (c *callClient) BatchCallContext(ctx context.Context, b []rpc.BatchElem) error {
+	start := time.Now()
+	err := c.Client.BatchCallContext(ctx, b)
+
+	methods := make([]string, len(b))
+	errors := make([]error, len(b))
+	for i, elem := range b {
+		methods[i] = elem.Method
+		errors[i] = elem.Error
+	}
+	metrics.ObserveRPCBatchCall(methods, start, err, errors)
+
+	return err
+}
@@ -14,11 +14,11 @@ type NetApi interface {
 }
 
 type netApi struct {
-	cc *rpc.Client
+	cc *callClient
 }
 
 func NewNetApi(cc *rpc.Client) NetApi {
-	return &netApi{cc: cc}
+	return &netApi{cc: newCallClient(cc)}
 }
 
 func (ni netApi) GetSyncInfo(ctx context.Context) (syncInfo *api.SyncInfo, err error) {
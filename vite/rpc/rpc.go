@@ -1,6 +1,8 @@
 package rpc
 
 import (
+	"context"
+
 	"github.com/vitelabs/go-vite/rpc"
 )
 
@@ -10,6 +12,9 @@ type RpcClient interface {
 	NetApi
 	UtilApi
 
+	CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error
+	BatchCallContext(ctx context.Context, calls []*BatchCall) error
+
 	GetClient() *rpc.Client
 }
 
@@ -24,7 +29,7 @@ func NewRpcClient(rawurl string) (RpcClient, error) {
 		ContractApi: NewContractApi(c),
 		NetApi:      NewNetApi(c),
 		UtilApi:     NewUtilApi(c),
-		cc:          c,
+		cc:          newCallClient(c),
 	}
 	return r, nil
 }
@@ -35,9 +40,19 @@ type rpcClient struct {
 	NetApi
 	UtilApi
 
-	cc *rpc.Client
+	cc *callClient
 }
 
 func (c rpcClient) GetClient() *rpc.Client {
-	return c.cc
+	return c.cc.Client
+}
+
+// CallContext calls an arbitrary gvite method.
+func (c rpcClient) CallContext(
+	ctx context.Context,
+	result interface{},
+	method string,
+	args ...interface{},
+) error {
+	return c.cc.CallContext(ctx, result, method, args...)
 }
@@ -0,0 +1,69 @@
+package rpc
+
+import (
+	"context"
+
+	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/rpc"
+)
+
+// subscribeNamespace is the namespace of the gvite subscription api.
+const subscribeNamespace = "subscribe"
+
+// SnapshotBlockEvent is a snapshot block notification,
+// Removed is set when the block was rolled back.
+type SnapshotBlockEvent struct {
+	Hash    types.Hash `json:"hash"`
+	Height  string     `json:"height"`
+	Removed bool       `json:"removed"`
+}
+
+// AccountBlockEvent is an account block notification,
+// Removed is set when the block was rolled back.
+type AccountBlockEvent struct {
+	Hash    types.Hash `json:"hash"`
+	Removed bool       `json:"removed"`
+}
+
+// SubscriptionClient subscribes to gvite notifications,
+// it requires a WebSocket connection to gvite.
+type SubscriptionClient interface {
+	SubscribeSnapshotBlocks(ctx context.Context, ch chan<- []*SnapshotBlockEvent) (*rpc.ClientSubscription, error)
+	SubscribeAccountBlocks(ctx context.Context, ch chan<- []*AccountBlockEvent) (*rpc.ClientSubscription, error)
+
+	Close()
+}
+
+// NewSubscriptionClient dials the gvite WebSocket server at rawurl.
+func NewSubscriptionClient(ctx context.Context, rawurl string) (SubscriptionClient, error) {
+	c, err := rpc.DialContext(ctx, rawurl)
+	if err != nil {
+		return nil, err
+	}
+
+	return &subscriptionClient{cc: c}, nil
+}
+
+type subscriptionClient struct {
+	cc *rpc.Client
+}
+
+// SubscribeSnapshotBlocks subscribes to new and rolled back snapshot blocks.
+func (sc subscriptionClient) SubscribeSnapshotBlocks(
+	ctx context.Context,
+	ch chan<- []*SnapshotBlockEvent,
+) (*rpc.ClientSubscription, error) {
+	return sc.cc.Subscribe(ctx, subscribeNamespace, ch, "createSnapshotBlockSubscription")
+}
+
+// SubscribeAccountBlocks subscribes to new and rolled back account blocks.
+func (sc subscriptionClient) SubscribeAccountBlocks(
+	ctx context.Context,
+	ch chan<- []*AccountBlockEvent,
+) (*rpc.ClientSubscription, error) {
+	return sc.cc.Subscribe(ctx, subscribeNamespace, ch, "createAccountBlockSubscription")
+}
+
+func (sc subscriptionClient) Close() {
+	sc.cc.Close()
+}
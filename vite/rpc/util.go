@@ -11,11 +11,11 @@ type UtilApi interface {
 }
 
 type utilApi struct {
-	cc *rpc.Client
+	cc *callClient
 }
 
 func NewUtilApi(cc *rpc.Client) UtilApi {
-	return &utilApi{cc: cc}
+	return &utilApi{cc: newCallClient(cc)}
 }
 
 func (ui utilApi) GetPoWNonce(
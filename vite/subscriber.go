@@ -0,0 +1,255 @@
+package vite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/azbuky/rosetta-vite/metrics"
+	"github.com/azbuky/rosetta-vite/vite/rpc"
+)
+
+const (
+	// subscriberMinBackoff is the delay before the
+	// subscriber reconnects after the connection dropped.
+	subscriberMinBackoff = time.Second
+
+	// subscriberMaxBackoff is the maximum delay before the
+	// subscriber reconnects, the delay doubles after every
+	// failed attempt.
+	subscriberMaxBackoff = 30 * time.Second
+
+	// subscriberStaleAfter is the age after which the tracked
+	// status is no longer used and gvite is polled instead.
+	subscriberStaleAfter = 30 * time.Second
+
+	// subscriberBufferSize is the number of notifications
+	// buffered for each subscription.
+	subscriberBufferSize = 128
+)
+
+// Subscriber subscribes to new snapshot and account blocks over a
+// WebSocket connection to gvite. It keeps the current status in
+// memory, evicts rolled back blocks from the block cache and wakes
+// the consumers of new snapshot blocks. The connection is reopened
+// whenever it drops, in the meantime the status is polled.
+type Subscriber struct {
+	client *Client
+	url    string
+
+	mu        sync.RWMutex
+	connected bool
+	current   *nodeStatus
+	updated   time.Time
+	listeners []chan struct{}
+}
+
+// newSubscriber creates a Subscriber for the gvite WebSocket
+// server at url, no Subscriber is created if url is empty.
+func newSubscriber(client *Client, url string) *Subscriber {
+	if len(url) == 0 {
+		return nil
+	}
+
+	return &Subscriber{
+		client: client,
+		url:    url,
+	}
+}
+
+// StartSubscriber subscribes to gvite notifications until
+// the context is canceled, if a subscription URL is set.
+func (ec *Client) StartSubscriber(ctx context.Context) error {
+	if ec.subscriber == nil {
+		return nil
+	}
+
+	return ec.subscriber.Start(ctx)
+}
+
+// Start subscribes to gvite notifications and
+// reconnects until the context is canceled.
+func (s *Subscriber) Start(ctx context.Context) error {
+	backoff := subscriberMinBackoff
+	for {
+		received, err := s.subscribe(ctx)
+		s.setConnected(false)
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		if received {
+			backoff = subscriberMinBackoff
+		}
+		log.Printf(
+			"subscriber %s: %s, polling gvite and reconnecting in %s",
+			s.client.network,
+			err.Error(),
+			backoff,
+		)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > subscriberMaxBackoff {
+			backoff = subscriberMaxBackoff
+		}
+	}
+}
+
+// subscribe opens a connection to gvite and handles notifications
+// until it drops. It returns true if any notification was received.
+func (s *Subscriber) subscribe(ctx context.Context) (bool, error) {
+	sc, err := rpc.NewSubscriptionClient(ctx, s.url)
+	if err != nil {
+		return false, fmt.Errorf("%w: unable to connect", err)
+	}
+	defer sc.Close()
+
+	snapshotBlocks := make(chan []*rpc.SnapshotBlockEvent, subscriberBufferSize)
+	snapshotSub, err := sc.SubscribeSnapshotBlocks(ctx, snapshotBlocks)
+	if err != nil {
+		return false, fmt.Errorf("%w: unable to subscribe to snapshot blocks", err)
+	}
+	defer snapshotSub.Unsubscribe()
+
+	accountBlocks := make(chan []*rpc.AccountBlockEvent, subscriberBufferSize)
+	accountSub, err := sc.SubscribeAccountBlocks(ctx, accountBlocks)
+	if err != nil {
+		return false, fmt.Errorf("%w: unable to subscribe to account blocks", err)
+	}
+	defer accountSub.Unsubscribe()
+
+	s.setConnected(true)
+	s.refresh(ctx)
+
+	received := false
+	for {
+		select {
+		case <-ctx.Done():
+			return received, nil
+		case err := <-snapshotSub.Err():
+			return received, subscriptionError("snapshot block", err)
+		case err := <-accountSub.Err():
+			return received, subscriptionError("account block", err)
+		case events := <-snapshotBlocks:
+			received = true
+			s.onSnapshotBlocks(ctx, events)
+		case events := <-accountBlocks:
+			received = true
+			s.onAccountBlocks(events)
+		}
+	}
+}
+
+// onSnapshotBlocks refreshes the status and wakes the consumers.
+func (s *Subscriber) onSnapshotBlocks(ctx context.Context, events []*rpc.SnapshotBlockEvent) {
+	for _, event := range events {
+		if event.Removed {
+			s.client.cache.invalidate()
+			break
+		}
+	}
+
+	s.refresh(ctx)
+	s.notify()
+}
+
+// onAccountBlocks evicts the block cache when account blocks are rolled back.
+func (s *Subscriber) onAccountBlocks(events []*rpc.AccountBlockEvent) {
+	for _, event := range events {
+		if event.Removed {
+			s.client.cache.invalidate()
+			return
+		}
+	}
+}
+
+// refresh polls the status once for every notification instead of
+// on every call to Status. On failure the tracked status is dropped
+// so that Status polls gvite.
+func (s *Subscriber) refresh(ctx context.Context) {
+	status, err := s.client.fetchStatus(ctx)
+	if err != nil && ctx.Err() == nil {
+		log.Printf("subscriber %s: %s", s.client.network, err.Error())
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.current = status
+	s.updated = time.Now()
+}
+
+// status returns the tracked status, or nil if the
+// subscription is not live or the status is stale.
+func (s *Subscriber) status() *nodeStatus {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if !s.connected || time.Since(s.updated) > subscriberStaleAfter {
+		return nil
+	}
+
+	return s.current
+}
+
+// notifications returns a channel receiving a value when a new
+// snapshot block is received. Notifications are dropped while the
+// previous one was not consumed. A nil Subscriber returns a nil
+// channel, which never receives.
+func (s *Subscriber) notifications() <-chan struct{} {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	listener := make(chan struct{}, 1)
+	s.listeners = append(s.listeners, listener)
+	return listener
+}
+
+func (s *Subscriber) notify() {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, listener := range s.listeners {
+		select {
+		case listener <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (s *Subscriber) setConnected(connected bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.connected = connected
+	if !connected {
+		s.current = nil
+	}
+	metrics.SetSubscriptionConnected(s.client.network, connected)
+}
+
+// subscriptionError describes why a subscription was closed.
+func subscriptionError(subscription string, err error) error {
+	if err == nil {
+		return errors.New(subscription + " subscription closed")
+	}
+
+	return fmt.Errorf("%w: %s subscription closed", err, subscription)
+}
@@ -0,0 +1,184 @@
+package vite
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/azbuky/rosetta-vite/metrics"
+)
+
+const (
+	// DefaultGviteMinBackoff is the default delay
+	// before gvite is restarted after a crash.
+	DefaultGviteMinBackoff = time.Second
+
+	// DefaultGviteMaxBackoff is the default maximum delay before
+	// gvite is restarted, the delay doubles after every crash.
+	// gvite running longer than the maximum delay resets it.
+	DefaultGviteMaxBackoff = time.Minute
+)
+
+// GviteState is the state of a supervised gvite process.
+type GviteState string
+
+const (
+	// GviteStarting is the state while the process is started.
+	GviteStarting GviteState = "starting"
+
+	// GviteRunning is the state while the process is running.
+	GviteRunning GviteState = "running"
+
+	// GviteCrashed is the state after the process exited.
+	GviteCrashed GviteState = "crashed"
+
+	// GviteRestarting is the state while waiting to restart the process.
+	GviteRestarting GviteState = "restarting"
+
+	// GviteStopped is the state after the supervisor stopped.
+	GviteStopped GviteState = "stopped"
+)
+
+// GviteStatus describes a supervised gvite process.
+type GviteStatus struct {
+	State          GviteState `json:"state"`
+	Since          time.Time  `json:"since"`
+	Restarts       int        `json:"restarts"`
+	LastExitReason string     `json:"last_exit_reason,omitempty"`
+	LastExitTime   *time.Time `json:"last_exit_time,omitempty"`
+}
+
+// GviteSupervisor runs a local gvite process and restarts it
+// with exponential backoff whenever it exits, so that a crash
+// of gvite does not stop rosetta-vite.
+type GviteSupervisor struct {
+	network    string
+	binary     string
+	arguments  string
+	minBackoff time.Duration
+	maxBackoff time.Duration
+
+	mu     sync.RWMutex
+	status GviteStatus
+}
+
+// NewGviteSupervisor creates a GviteSupervisor for the gvite
+// node of a network started from binary with arguments.
+func NewGviteSupervisor(network string, binary string, arguments string) *GviteSupervisor {
+	return &GviteSupervisor{
+		network:    network,
+		binary:     binary,
+		arguments:  arguments,
+		minBackoff: DefaultGviteMinBackoff,
+		maxBackoff: DefaultGviteMaxBackoff,
+		status: GviteStatus{
+			State: GviteStarting,
+			Since: time.Now(),
+		},
+	}
+}
+
+// Status returns a copy of the status of the gvite process.
+func (s *GviteSupervisor) Status() *GviteStatus {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	status := s.status
+	return &status
+}
+
+// Running returns true if the gvite process is running.
+func (s *GviteSupervisor) Running() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.status.State == GviteRunning
+}
+
+// Run starts gvite and restarts it whenever it exits
+// until the context is canceled.
+func (s *GviteSupervisor) Run(ctx context.Context) error {
+	backoff := s.minBackoff
+	for {
+		s.setState(GviteStarting)
+		started := time.Now()
+		err := runGvite(ctx, s.binary, s.arguments, func() {
+			s.setState(GviteRunning)
+		})
+
+		if ctx.Err() != nil {
+			s.setState(GviteStopped)
+			return nil
+		}
+
+		reason := "exited"
+		if err != nil {
+			reason = err.Error()
+		}
+		s.crashed(reason)
+
+		if time.Since(started) > s.maxBackoff {
+			backoff = s.minBackoff
+		}
+		log.Printf("gvite %s: %s, restarting in %s", s.network, reason, backoff)
+
+		s.setState(GviteRestarting)
+		select {
+		case <-ctx.Done():
+			s.setState(GviteStopped)
+			return nil
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > s.maxBackoff {
+			backoff = s.maxBackoff
+		}
+		s.restarted()
+	}
+}
+
+func (s *GviteSupervisor) setState(state GviteState) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.status.State = state
+	s.status.Since = time.Now()
+	metrics.SetGviteRunning(s.network, state == GviteRunning)
+}
+
+func (s *GviteSupervisor) crashed(reason string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.status.State = GviteCrashed
+	s.status.Since = now
+	s.status.LastExitReason = reason
+	s.status.LastExitTime = &now
+	metrics.SetGviteRunning(s.network, false)
+}
+
+func (s *GviteSupervisor) restarted() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.status.Restarts++
+	metrics.ObserveGviteRestart(s.network)
+}
+
+// gviteNotRunningError describes why a supervised gvite is not running.
+func gviteNotRunningError(status *GviteStatus) error {
+	if len(status.LastExitReason) == 0 {
+		return fmt.Errorf("gvite is %s", status.State)
+	}
+
+	return fmt.Errorf(
+		"gvite is %s after %d restarts, last exit: %s",
+		status.State,
+		status.Restarts,
+		status.LastExitReason,
+	)
+}
@@ -0,0 +1,223 @@
+package vite
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/azbuky/rosetta-vite/vite/rpc"
+	"github.com/coinbase/rosetta-sdk-go/types"
+
+	viteTypes "github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/rpcapi/api"
+)
+
+const (
+	// tokenRefreshInterval is the time the token registry
+	// waits between two refreshes of the token list.
+	tokenRefreshInterval = 10 * time.Minute
+
+	// tokenInfoListPageSize is the number of tokens
+	// requested from gvite in a single call.
+	tokenInfoListPageSize = uint64(100)
+)
+
+// TokenRegistry caches the token info of all tokens so that
+// currencies are built from the same token info everywhere.
+// It is loaded from contract_getTokenInfoList, tokens missing
+// from the registry are fetched on demand.
+type TokenRegistry struct {
+	c rpc.RpcClient
+
+	mu     sync.RWMutex
+	tokens map[viteTypes.TokenTypeId]*api.RpcTokenInfo
+}
+
+// NewTokenRegistry creates an empty TokenRegistry.
+func NewTokenRegistry(c rpc.RpcClient) *TokenRegistry {
+	return &TokenRegistry{
+		c:      c,
+		tokens: map[viteTypes.TokenTypeId]*api.RpcTokenInfo{},
+	}
+}
+
+// Start warms the registry with the full token list and refreshes it
+// periodically until the context is canceled. Errors are logged and
+// retried on the next interval.
+func (r *TokenRegistry) Start(ctx context.Context) error {
+	for {
+		if err := r.Refresh(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("token registry: %s", err.Error())
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(tokenRefreshInterval):
+		}
+	}
+}
+
+// Refresh loads the full token list from gvite.
+func (r *TokenRegistry) Refresh(ctx context.Context) error {
+	tokens := map[viteTypes.TokenTypeId]*api.RpcTokenInfo{}
+	for page := uint64(0); ; page++ {
+		tokenInfoList, err := r.c.GetTokenInfoList(ctx, page, tokenInfoListPageSize)
+		if err != nil {
+			return fmt.Errorf("%w: unable to get token info list", err)
+		}
+
+		for _, tokenInfo := range tokenInfoList.List {
+			tokens[tokenInfo.TokenId] = tokenInfo
+		}
+
+		if uint64(len(tokenInfoList.List)) < tokenInfoListPageSize ||
+			len(tokens) >= tokenInfoList.Count {
+			break
+		}
+	}
+
+	r.mu.Lock()
+	r.tokens = tokens
+	r.mu.Unlock()
+
+	return nil
+}
+
+// TokenInfo returns the token info of tokenId, fetching
+// it from gvite if it is not in the registry.
+func (r *TokenRegistry) TokenInfo(
+	ctx context.Context,
+	tokenId viteTypes.TokenTypeId,
+) (*api.RpcTokenInfo, error) {
+	r.mu.RLock()
+	tokenInfo, ok := r.tokens[tokenId]
+	r.mu.RUnlock()
+	if ok {
+		return tokenInfo, nil
+	}
+
+	tokenInfo, err := r.c.GetTokenInfoById(ctx, tokenId.Hex())
+	if err != nil {
+		return nil, err
+	}
+	r.add(tokenInfo)
+
+	return tokenInfo, nil
+}
+
+// TokenInfos returns the token info of every tokenId. Tokens missing
+// from the registry are fetched in a single batch request.
+func (r *TokenRegistry) TokenInfos(
+	ctx context.Context,
+	tokenIds []viteTypes.TokenTypeId,
+) ([]*api.RpcTokenInfo, error) {
+	missing := map[viteTypes.TokenTypeId]*api.RpcTokenInfo{}
+	calls := []*rpc.BatchCall{}
+
+	r.mu.RLock()
+	for _, tokenId := range tokenIds {
+		if _, ok := r.tokens[tokenId]; ok {
+			continue
+		}
+		if _, ok := missing[tokenId]; ok {
+			continue
+		}
+		call, tokenInfo := rpc.NewGetTokenInfoByIdCall(tokenId.Hex())
+		missing[tokenId] = tokenInfo
+		calls = append(calls, call)
+	}
+	r.mu.RUnlock()
+
+	if len(calls) > 0 {
+		if err := r.c.BatchCallContext(ctx, calls); err != nil {
+			return nil, err
+		}
+		for _, call := range calls {
+			if call.Error != nil {
+				return nil, call.Error
+			}
+		}
+		for _, tokenInfo := range missing {
+			r.add(tokenInfo)
+		}
+	}
+
+	tokenInfos := make([]*api.RpcTokenInfo, len(tokenIds))
+	r.mu.RLock()
+	for i, tokenId := range tokenIds {
+		tokenInfo, ok := r.tokens[tokenId]
+		if !ok {
+			tokenInfo = missing[tokenId]
+		}
+		tokenInfos[i] = tokenInfo
+	}
+	r.mu.RUnlock()
+
+	return tokenInfos, nil
+}
+
+// Currencies returns the currency of every tokenId, built with
+// ViteTokenToCurrency so that all endpoints report the same currency.
+// An ErrCurrencyInvalid error is returned for unknown tokens.
+func (r *TokenRegistry) Currencies(
+	ctx context.Context,
+	tokenIds []viteTypes.TokenTypeId,
+) ([]*types.Currency, error) {
+	tokenInfos, err := r.TokenInfos(ctx, tokenIds)
+	if err != nil {
+		return nil, err
+	}
+
+	currencies := make([]*types.Currency, len(tokenIds))
+	for i, tokenId := range tokenIds {
+		tokenInfo := tokenInfos[i]
+		if tokenInfo == nil || tokenInfo.TokenId != tokenId {
+			return nil, fmt.Errorf("%w: unknown token %s", ErrCurrencyInvalid, tokenId.Hex())
+		}
+		currency := ViteTokenToCurrency(tokenId.Hex(), *tokenInfo)
+		currencies[i] = &currency
+	}
+
+	return currencies, nil
+}
+
+// annotate sets the token info of account blocks from the registry,
+// so that their currencies match the ones in /account/balance.
+func (r *TokenRegistry) annotate(
+	ctx context.Context,
+	accountBlocks ...*api.AccountBlock,
+) error {
+	tokenIds := make([]viteTypes.TokenTypeId, len(accountBlocks))
+	for i, accountBlock := range accountBlocks {
+		tokenIds[i] = accountBlock.TokenId
+	}
+
+	tokenInfos, err := r.TokenInfos(ctx, tokenIds)
+	if err != nil {
+		return err
+	}
+
+	for i, accountBlock := range accountBlocks {
+		// keep the token info of blocks with an unknown token
+		if tokenInfos[i].TokenId == accountBlock.TokenId {
+			accountBlock.TokenInfo = tokenInfos[i]
+		}
+	}
+
+	return nil
+}
+
+// add stores a token info unless it is empty,
+// gvite returns an empty token info for unknown tokens.
+func (r *TokenRegistry) add(tokenInfo *api.RpcTokenInfo) {
+	if tokenInfo == nil || tokenInfo.TokenId == viteTypes.ZERO_TOKENID {
+		return
+	}
+
+	r.mu.Lock()
+	r.tokens[tokenInfo.TokenId] = tokenInfo
+	r.mu.Unlock()
+}
@@ -0,0 +1,54 @@
+package vite
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/azbuky/rosetta-vite/vite/rpc"
+	"github.com/coinbase/rosetta-sdk-go/types"
+
+	viteTypes "github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/ledger"
+	"github.com/vitelabs/go-vite/rpcapi/api"
+)
+
+// testTokenInfos answers contract_getTokenInfoById calls
+// with an empty token info, as gvite does for unknown tokens.
+type testTokenInfos struct {
+	rpc.RpcClient
+}
+
+func (c *testTokenInfos) BatchCallContext(ctx context.Context, calls []*rpc.BatchCall) error {
+	return nil
+}
+
+func TestTokenRegistryCurrencies(t *testing.T) {
+	registry := NewTokenRegistry(&testTokenInfos{})
+	registry.add(&api.RpcTokenInfo{
+		TokenSymbol: "VITE",
+		Decimals:    18,
+		TokenId:     ledger.ViteTokenId,
+	})
+
+	currencies, err := registry.Currencies(context.Background(), []viteTypes.TokenTypeId{ledger.ViteTokenId})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := ViteTokenToCurrency(ledger.ViteTokenId.Hex(), api.RpcTokenInfo{
+		TokenSymbol: "VITE",
+		Decimals:    18,
+	})
+	if types.Hash(currencies[0]) != types.Hash(&expected) {
+		t.Fatalf("expected %s, got %s", types.PrintStruct(expected), types.PrintStruct(currencies[0]))
+	}
+
+	unknown, err := viteTypes.HexToTokenTypeId("tti_251a3e67a41b5ea2373936c8")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = registry.Currencies(context.Background(), []viteTypes.TokenTypeId{ledger.ViteTokenId, unknown})
+	if !errors.Is(err, ErrCurrencyInvalid) {
+		t.Fatalf("expected ErrCurrencyInvalid, got %v", err)
+	}
+}
@@ -0,0 +1,403 @@
+package vite
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/syndtr/goleveldb/leveldb"
+
+	viteTypes "github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/rpcapi/api"
+)
+
+const (
+	// trackerSyncInterval is the time the tracker waits
+	// before checking for new snapshot blocks.
+	trackerSyncInterval = 5 * time.Second
+
+	// EventsDefaultLimit is the number of events returned
+	// by /events/blocks when no limit is provided.
+	EventsDefaultLimit = int64(100)
+
+	// EventsMaxLimit is the maximum number of events returned
+	// by /events/blocks in a single call.
+	EventsMaxLimit = int64(1000)
+)
+
+var (
+	trackedHeightKey = []byte("tracked-height")
+	startHeightKey   = []byte("start-height")
+	eventSequenceKey = []byte("event-sequence")
+
+	snapshotKeyPrefix = []byte("snapshot/")
+	eventKeyPrefix    = []byte("event/")
+)
+
+// blockHandler is notified by the Tracker when a snapshot block
+// is added to or removed from the tracked chain. Changes are
+// written to the batch so they are stored atomically with the
+// tracked chain.
+type blockHandler interface {
+	addBlock(ctx context.Context, batch *leveldb.Batch, block *api.SnapshotBlock) error
+	removeBlock(batch *leveldb.Batch, height uint64) error
+}
+
+// Tracker follows the snapshot chain and stores the hash of every
+// tracked snapshot block. Each new block's PreviousHash is compared
+// with the stored chain so that rollbacks are detected, and every
+// change is recorded as a block_added or block_removed event.
+type Tracker struct {
+	client *Client
+	db     *leveldb.DB
+
+	fromGenesis bool
+	handlers    []blockHandler
+}
+
+// NewTracker opens (or creates) the tracker database at path.
+// If fromGenesis is false an empty database starts tracking
+// at the current tip.
+func NewTracker(client *Client, path string, fromGenesis bool) (*Tracker, error) {
+	db, err := leveldb.OpenFile(path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unable to open database at %s", err, path)
+	}
+
+	tracker := &Tracker{
+		client:      client,
+		db:          db,
+		fromGenesis: fromGenesis,
+	}
+	if err := tracker.checkStartHeight(path); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return tracker, nil
+}
+
+// checkStartHeight refuses a database that started tracking at the
+// tip when the tracker must track from genesis, e.g. when the indexer
+// is enabled on a database created with events only. The transaction
+// index would otherwise be missing all blocks before the start height.
+func (t *Tracker) checkStartHeight(path string) error {
+	start, err := t.StartHeight()
+	if err != nil {
+		return err
+	}
+
+	if t.fromGenesis && start > uint64(GenesisBlockIndex) {
+		return fmt.Errorf(
+			"database at %s started tracking at snapshot block %d and cannot be indexed from genesis, remove it to reindex",
+			path,
+			start,
+		)
+	}
+
+	return nil
+}
+
+// StartHeight returns the height of the first tracked snapshot
+// block, 0 if no snapshot block was tracked yet.
+func (t *Tracker) StartHeight() (uint64, error) {
+	return t.getUint64(startHeightKey)
+}
+
+// Close closes the underlying database.
+func (t *Tracker) Close() error {
+	return t.db.Close()
+}
+
+// Start tracks new snapshot blocks until the context is canceled.
+// Errors while syncing are logged and retried on the next interval,
+// or as soon as the subscriber receives a new snapshot block.
+func (t *Tracker) Start(ctx context.Context) error {
+	newBlocks := t.client.subscriber.notifications()
+	for {
+		if err := t.sync(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("tracker: %s", err.Error())
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(trackerSyncInterval):
+		case <-newBlocks:
+		}
+	}
+}
+
+// TrackedHeight returns the height of the last tracked snapshot block.
+func (t *Tracker) TrackedHeight() (uint64, error) {
+	return t.getUint64(trackedHeightKey)
+}
+
+// sync removes the tracked blocks that were rolled back and
+// tracks all snapshot blocks up to the current tip.
+func (t *Tracker) sync(ctx context.Context) error {
+	tip, err := t.client.getSnapshotBlock(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	height, err := t.rollback(ctx, tip)
+	if err != nil {
+		return err
+	}
+
+	for height < tip.Height {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		next := height + 1
+		if height == 0 && !t.fromGenesis {
+			next = tip.Height
+		}
+
+		block, err := t.client.c.GetSnapshotBlockByHeight(ctx, next)
+		if err != nil {
+			return err
+		}
+
+		// the snapshot chain rolled back, drop the last tracked block.
+		// There is no stored parent for the first tracked block.
+		if height > 0 {
+			parentHash, err := t.snapshotHash(height)
+			if err != nil && err != leveldb.ErrNotFound {
+				return err
+			}
+			if err == nil && parentHash != block.PreviousHash {
+				if err := t.removeBlock(height, parentHash); err != nil {
+					return err
+				}
+				height--
+				continue
+			}
+		}
+
+		if err := t.addBlock(ctx, block); err != nil {
+			return err
+		}
+		height = next
+	}
+
+	return nil
+}
+
+// rollback removes the tracked blocks that are no longer part of the
+// snapshot chain, either because the chain rolled back below them or
+// because they were replaced by a fork at the same height. It returns
+// the height of the last tracked block still on the chain.
+func (t *Tracker) rollback(ctx context.Context, tip *api.SnapshotBlock) (uint64, error) {
+	height, err := t.TrackedHeight()
+	if err != nil {
+		return 0, err
+	}
+
+	for height > 0 {
+		if ctx.Err() != nil {
+			return 0, ctx.Err()
+		}
+
+		hash, err := t.snapshotHash(height)
+		if err == leveldb.ErrNotFound {
+			// there is no stored block before the first tracked block
+			return height, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+
+		if height <= tip.Height {
+			current := tip
+			if height < tip.Height {
+				current, err = t.client.c.GetSnapshotBlockByHeight(ctx, height)
+				if err != nil {
+					return 0, err
+				}
+				if current == nil {
+					return 0, fmt.Errorf("snapshot block %d not found", height)
+				}
+			}
+			if current.Hash == hash {
+				return height, nil
+			}
+		}
+
+		if err := t.removeBlock(height, hash); err != nil {
+			return 0, err
+		}
+		height--
+	}
+
+	return height, nil
+}
+
+// snapshotHash returns the stored hash of the snapshot block at height.
+func (t *Tracker) snapshotHash(height uint64) (viteTypes.Hash, error) {
+	value, err := t.db.Get(snapshotKey(height), nil)
+	if err != nil {
+		return viteTypes.Hash{}, err
+	}
+	return viteTypes.BytesToHash(value)
+}
+
+// addBlock stores a snapshot block and its block_added event.
+func (t *Tracker) addBlock(ctx context.Context, block *api.SnapshotBlock) error {
+	batch := new(leveldb.Batch)
+	for _, handler := range t.handlers {
+		if err := handler.addBlock(ctx, batch, block); err != nil {
+			return err
+		}
+	}
+
+	start, err := t.getUint64(startHeightKey)
+	if err != nil {
+		return err
+	}
+	if start == 0 {
+		batch.Put(startHeightKey, encodeUint64(block.Height))
+	}
+
+	batch.Put(snapshotKey(block.Height), block.Hash.Bytes())
+	batch.Put(trackedHeightKey, encodeUint64(block.Height))
+	if err := t.putEvent(batch, types.ADDED, block.Height, block.Hash); err != nil {
+		return err
+	}
+
+	return t.db.Write(batch, nil)
+}
+
+// removeBlock deletes the snapshot block at height and
+// stores its block_removed event.
+func (t *Tracker) removeBlock(height uint64, hash viteTypes.Hash) error {
+	batch := new(leveldb.Batch)
+	for _, handler := range t.handlers {
+		if err := handler.removeBlock(batch, height); err != nil {
+			return err
+		}
+	}
+
+	batch.Delete(snapshotKey(height))
+	batch.Put(trackedHeightKey, encodeUint64(height-1))
+	if err := t.putEvent(batch, types.REMOVED, height, hash); err != nil {
+		return err
+	}
+
+	return t.db.Write(batch, nil)
+}
+
+// putEvent appends an event with the next sequence to the batch.
+func (t *Tracker) putEvent(
+	batch *leveldb.Batch,
+	eventType types.BlockEventType,
+	height uint64,
+	hash viteTypes.Hash,
+) error {
+	sequence, err := t.getUint64(eventSequenceKey)
+	if err != nil {
+		return err
+	}
+
+	value, err := json.Marshal(&types.BlockEvent{
+		Sequence: int64(sequence),
+		BlockIdentifier: &types.BlockIdentifier{
+			Hash:  hash.Hex(),
+			Index: int64(height),
+		},
+		Type: eventType,
+	})
+	if err != nil {
+		return err
+	}
+
+	batch.Put(eventKey(sequence), value)
+	batch.Put(eventSequenceKey, encodeUint64(sequence+1))
+
+	return nil
+}
+
+// EventsBlocks returns the block events starting at offset. If offset
+// is nil the last events before the tip are returned.
+func (t *Tracker) EventsBlocks(
+	ctx context.Context,
+	offset *int64,
+	limit *int64,
+) (*types.EventsBlocksResponse, error) {
+	count, err := t.getUint64(eventSequenceKey)
+	if err != nil {
+		return nil, err
+	}
+
+	eventsLimit := EventsDefaultLimit
+	if limit != nil {
+		eventsLimit = *limit
+	}
+	if eventsLimit > EventsMaxLimit {
+		eventsLimit = EventsMaxLimit
+	}
+
+	start := int64(count) - eventsLimit
+	if offset != nil {
+		start = *offset
+	}
+	if start < 0 {
+		start = 0
+	}
+
+	events := []*types.BlockEvent{}
+	for sequence := start; sequence < start+eventsLimit && sequence < int64(count); sequence++ {
+		value, err := t.db.Get(eventKey(uint64(sequence)), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var event types.BlockEvent
+		if err := json.Unmarshal(value, &event); err != nil {
+			return nil, err
+		}
+		events = append(events, &event)
+	}
+
+	maxSequence := int64(0)
+	if count > 0 {
+		maxSequence = int64(count) - 1
+	}
+
+	return &types.EventsBlocksResponse{
+		MaxSequence: maxSequence,
+		Events:      events,
+	}, nil
+}
+
+// getUint64 reads a big endian uint64, missing keys are read as 0.
+func (t *Tracker) getUint64(key []byte) (uint64, error) {
+	value, err := t.db.Get(key, nil)
+	if err == leveldb.ErrNotFound {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint64(value), nil
+}
+
+func encodeUint64(value uint64) []byte {
+	encoded := make([]byte, 8)
+	binary.BigEndian.PutUint64(encoded, value)
+	return encoded
+}
+
+func snapshotKey(height uint64) []byte {
+	return append(append([]byte{}, snapshotKeyPrefix...), encodeUint64(height)...)
+}
+
+func eventKey(sequence uint64) []byte {
+	return append(append([]byte{}, eventKeyPrefix...), encodeUint64(sequence)...)
+}
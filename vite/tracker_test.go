@@ -0,0 +1,248 @@
+package vite
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+
+	"github.com/azbuky/rosetta-vite/vite/rpc"
+	viteTypes "github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/rpcapi/api"
+)
+
+// testSnapshotChain serves a snapshot chain to the tracker,
+// it implements only the rpc.RpcClient methods used by sync.
+type testSnapshotChain struct {
+	rpc.RpcClient
+
+	blocks []*api.SnapshotBlock
+}
+
+// extend appends blocks on top of height, replacing all
+// blocks above it with blocks of the given fork.
+func (c *testSnapshotChain) extend(height uint64, count int, fork byte) {
+	c.blocks = c.blocks[:height]
+	for i := 0; i < count; i++ {
+		block := testSnapshotBlock(uint64(len(c.blocks))+1, fork)
+		if len(c.blocks) > 0 {
+			block.PreviousHash = c.blocks[len(c.blocks)-1].Hash
+		}
+		c.blocks = append(c.blocks, block)
+	}
+}
+
+func (c *testSnapshotChain) GetLatestSnapshotHash(ctx context.Context) (*viteTypes.Hash, error) {
+	hash := c.blocks[len(c.blocks)-1].Hash
+	return &hash, nil
+}
+
+func (c *testSnapshotChain) GetSnapshotBlockByHash(
+	ctx context.Context,
+	hash viteTypes.Hash,
+) (*api.SnapshotBlock, error) {
+	for _, block := range c.blocks {
+		if block.Hash == hash {
+			return block, nil
+		}
+	}
+	return nil, nil
+}
+
+func (c *testSnapshotChain) GetSnapshotBlockByHeight(
+	ctx context.Context,
+	height uint64,
+) (*api.SnapshotBlock, error) {
+	if height == 0 || height > uint64(len(c.blocks)) {
+		return nil, nil
+	}
+	return c.blocks[height-1], nil
+}
+
+// newTestTracker opens a tracker in a temporary directory,
+// the returned function closes and removes it.
+func newTestTracker(t *testing.T, chain *testSnapshotChain, fromGenesis bool) (*Tracker, func()) {
+	dir, err := ioutil.TempDir("", "tracker")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tracker, err := NewTracker(&Client{c: chain}, dir, fromGenesis)
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+
+	return tracker, func() {
+		tracker.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+type testEvent struct {
+	eventType types.BlockEventType
+	height    uint64
+	fork      byte
+}
+
+func TestTrackerRollback(t *testing.T) {
+	tests := []struct {
+		name        string
+		forkHeight  uint64
+		forkBlocks  int
+		fromGenesis bool
+		expected    []testEvent
+	}{
+		{
+			name:        "one block",
+			forkHeight:  2,
+			forkBlocks:  2,
+			fromGenesis: true,
+			expected: []testEvent{
+				{types.ADDED, 1, 0},
+				{types.ADDED, 2, 0},
+				{types.ADDED, 3, 0},
+				{types.REMOVED, 3, 0},
+				{types.ADDED, 3, 1},
+				{types.ADDED, 4, 1},
+			},
+		},
+		{
+			name:        "two blocks",
+			forkHeight:  1,
+			forkBlocks:  3,
+			fromGenesis: true,
+			expected: []testEvent{
+				{types.ADDED, 1, 0},
+				{types.ADDED, 2, 0},
+				{types.ADDED, 3, 0},
+				{types.REMOVED, 3, 0},
+				{types.REMOVED, 2, 0},
+				{types.ADDED, 2, 1},
+				{types.ADDED, 3, 1},
+				{types.ADDED, 4, 1},
+			},
+		},
+		{
+			name:        "fork at the same height",
+			forkHeight:  2,
+			forkBlocks:  1,
+			fromGenesis: true,
+			expected: []testEvent{
+				{types.ADDED, 1, 0},
+				{types.ADDED, 2, 0},
+				{types.ADDED, 3, 0},
+				{types.REMOVED, 3, 0},
+				{types.ADDED, 3, 1},
+			},
+		},
+		{
+			name:        "lower tip",
+			forkHeight:  1,
+			forkBlocks:  0,
+			fromGenesis: true,
+			expected: []testEvent{
+				{types.ADDED, 1, 0},
+				{types.ADDED, 2, 0},
+				{types.ADDED, 3, 0},
+				{types.REMOVED, 3, 0},
+				{types.REMOVED, 2, 0},
+			},
+		},
+		{
+			name:        "fork below a lower tip",
+			forkHeight:  1,
+			forkBlocks:  1,
+			fromGenesis: true,
+			expected: []testEvent{
+				{types.ADDED, 1, 0},
+				{types.ADDED, 2, 0},
+				{types.ADDED, 3, 0},
+				{types.REMOVED, 3, 0},
+				{types.REMOVED, 2, 0},
+				{types.ADDED, 2, 1},
+			},
+		},
+		{
+			// there is no stored parent of the first tracked block,
+			// so the rollback stops at the start height
+			name:        "below start height",
+			forkHeight:  1,
+			forkBlocks:  3,
+			fromGenesis: false,
+			expected: []testEvent{
+				{types.ADDED, 3, 0},
+				{types.REMOVED, 3, 0},
+				{types.ADDED, 3, 1},
+				{types.ADDED, 4, 1},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			chain := &testSnapshotChain{}
+			chain.extend(0, 3, 0)
+			tracker, closeTracker := newTestTracker(t, chain, test.fromGenesis)
+			defer closeTracker()
+
+			ctx := context.Background()
+			if err := tracker.sync(ctx); err != nil {
+				t.Fatal(err)
+			}
+
+			chain.extend(test.forkHeight, test.forkBlocks, 1)
+			if err := tracker.sync(ctx); err != nil {
+				t.Fatal(err)
+			}
+
+			response, err := tracker.EventsBlocks(ctx, nil, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expected := make([]*types.BlockEvent, len(test.expected))
+			for i, event := range test.expected {
+				expected[i] = &types.BlockEvent{
+					Sequence: int64(i),
+					BlockIdentifier: &types.BlockIdentifier{
+						Hash:  testSnapshotBlock(event.height, event.fork).Hash.Hex(),
+						Index: int64(event.height),
+					},
+					Type: event.eventType,
+				}
+			}
+			if !reflect.DeepEqual(response.Events, expected) {
+				t.Fatalf("expected events %v, got %v", types.PrintStruct(expected), types.PrintStruct(response.Events))
+			}
+			if response.MaxSequence != int64(len(expected)-1) {
+				t.Fatalf("expected max sequence %d, got %d", len(expected)-1, response.MaxSequence)
+			}
+
+			height, err := tracker.TrackedHeight()
+			if err != nil {
+				t.Fatal(err)
+			}
+			tipHeight := uint64(len(chain.blocks))
+			if height != tipHeight {
+				t.Fatalf("expected tracked height %d, got %d", tipHeight, height)
+			}
+			start, err := tracker.StartHeight()
+			if err != nil {
+				t.Fatal(err)
+			}
+			for h := start; h <= tipHeight; h++ {
+				hash, err := tracker.snapshotHash(h)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if hash != chain.blocks[h-1].Hash {
+					t.Fatalf("snapshot %d does not match the chain", h)
+				}
+			}
+		})
+	}
+}
@@ -1,9 +1,8 @@
 package vite
 
 import (
-	"fmt"
-
 	"github.com/coinbase/rosetta-sdk-go/types"
+	viteTypes "github.com/vitelabs/go-vite/common/types"
 )
 
 const (
@@ -55,6 +54,12 @@ const (
 
 	ExceedMaxDepthStatus string = "EXCEED_MAX_DEPTH"
 
+	// Create contract parameter limits enforced by gvite
+	DefaultQuotaMultiplier uint8 = 10
+	MinQuotaMultiplier     uint8 = 10
+	MaxQuotaMultiplier     uint8 = 100
+	MaxConfirmTimes        uint8 = 75
+
 	// Known addresses
 	MintAddress string = "vite_000000000000000000000000000000000000000595292d996d"
 
@@ -66,9 +71,6 @@ const (
 	// genesis block.
 	GenesisBlockIndex = int64(1)
 
-	// MainnetGviteArguments are the arguments to start a mainnet gvite instance.
-	MainnetGviteArguments = `--config=/app/vite/node_config.json`
-
 	// IncludeMempoolCoins does not apply to rosetta-vite as it is not UTXO-based.
 	IncludeMempoolCoins = false
 
@@ -78,12 +80,16 @@ const (
 
 	MetadataToAddressKey     string = "toAddress"
 	MetadataSendBlockHashKey string = "sendBlockHash"
+	MetadataBytecodeKey      string = "bytecode"
+
+	// Construction preprocess metadata keys
+	// used to encode a contract call
+	MetadataContractAbiKey string = "contract_abi"
+	MetadataMethodNameKey  string = "method_name"
+	MetadataMethodArgsKey  string = "method_args"
 )
 
 var (
-	// TestnetGviteArguments are the arguments to start a testnet gvite instance.
-	TestnetGviteArguments = fmt.Sprintf("%s --networkid 2", MainnetGviteArguments)
-
 	// Currency is the *types.Currency for all
 	// Vite networks.
 	Currency = &types.Currency{
@@ -118,9 +124,14 @@ var (
 			Status:     ExceedMaxDepthStatus,
 			Successful: false,
 		},
+		{
+			Status:     IntentStatus,
+			Successful: false,
+		},
 	}
 
 	// CallMethods are all supported call methods.
+	// It is populated from the call method schemas.
 	CallMethods = []string{}
 )
 
@@ -133,14 +144,23 @@ type ConstructionOptions struct {
 	FetchPreviousBlock string                  `json:"fetch_previous_block"`
 	UsePow             string                  `json:"use_pow"`
 	Data               []byte                  `json:"data,omitempty"`
+	ContractAbi        string                  `json:"contract_abi,omitempty"`
+	ConstructorArgs    []byte                  `json:"constructor_args,omitempty"`
+	// Contract is only set for CREATE_CONTRACT
+	Contract *ContractDescription `json:"contract,omitempty"`
 }
 
 // Defines construction metadata
 type ConstructionMetadata struct {
-	Height       uint64  `json:"height"`
-	PreviousHash string  `json:"previousHash"`
-	Difficulty   *string `json:"difficulty,omitempty"`
-	Nonce        *string `json:"nonce,omitempty"`
+	Height          uint64  `json:"height"`
+	PreviousHash    string  `json:"previousHash"`
+	Difficulty      *string `json:"difficulty,omitempty"`
+	Nonce           *string `json:"nonce,omitempty"`
+	Data            []byte  `json:"data,omitempty"`
+	ContractAbi     string  `json:"contractAbi,omitempty"`
+	ConstructorArgs []byte  `json:"constructorArgs,omitempty"`
+	// PowDecision is only set when use_pow is auto
+	PowDecision *PowDecision `json:"powDecision,omitempty"`
 }
 
 // Defines transaction description from matched operations
@@ -154,6 +174,17 @@ type TransactionDescription struct {
 	Amount types.Amount
 	Fee    *types.Amount
 	Data   []byte
+	// Contract is only set for CREATE_CONTRACT,
+	// Data then contains the contract bytecode
+	Contract *ContractDescription
+}
+
+// Defines the create contract parameters of a transaction description
+type ContractDescription struct {
+	Gid             viteTypes.Gid `json:"gid"`
+	ConfirmTimes    uint8         `json:"confirmTimes"`
+	SeedCount       uint8         `json:"seedCount"`
+	QuotaMultiplier uint8         `json:"quotaMultiplier"`
 }
 
 // Defines Request Operation metadata
@@ -162,6 +193,16 @@ type RequestOperationMetadata struct {
 	Data      []byte `json:"data,omitempty"`
 }
 
+// Defines Create Contract Operation metadata
+type CreateContractOperationMetadata struct {
+	// Bytecode is the hex encoded contract bytecode
+	Bytecode        string `json:"bytecode"`
+	Gid             string `json:"gid,omitempty"`
+	ConfirmTimes    uint8  `json:"confirmTimes,omitempty"`
+	SeedCount       uint8  `json:"seedCount,omitempty"`
+	QuotaMultiplier uint8  `json:"quotaMultiplier,omitempty"`
+}
+
 // Defines Response Operation Metadata
 type ResponseOperationMetadata struct {
 	SendBlockHash string `json:"sendBlockHash"`
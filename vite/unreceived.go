@@ -0,0 +1,94 @@
+package vite
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+
+	viteTypes "github.com/vitelabs/go-vite/common/types"
+)
+
+// UnreceivedSubAccount is the sub account whose balance is the
+// total amount of the unreceived (on-road) send blocks of an address.
+const UnreceivedSubAccount = "unreceived"
+
+// unreceivedBalance returns the amounts sent to address that are not
+// received yet, per token. Unreceived blocks are only known for the
+// latest snapshot block, which is returned as the block identifier.
+func (ec *Client) unreceivedBalance(
+	ctx context.Context,
+	address viteTypes.Address,
+	currencies []*types.Currency,
+	blockIdentifier *types.PartialBlockIdentifier,
+) (*types.AccountBalanceResponse, error) {
+	if blockIdentifier != nil && (blockIdentifier.Hash != nil || blockIdentifier.Index != nil) {
+		return nil, fmt.Errorf("%s balances are only available for the current block", UnreceivedSubAccount)
+	}
+
+	block, err := ec.getSnapshotBlock(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	sendBlocks, err := ec.unreceivedBlocks(ctx, address, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	amounts := map[viteTypes.TokenTypeId]*big.Int{}
+	for _, sendBlock := range sendBlocks {
+		amount := new(big.Int)
+		if sendBlock.Amount != nil {
+			if _, ok := amount.SetString(*sendBlock.Amount, 10); !ok {
+				return nil, fmt.Errorf("invalid amount %s in block %s", *sendBlock.Amount, sendBlock.Hash)
+			}
+		}
+		if total, ok := amounts[sendBlock.TokenId]; ok {
+			total.Add(total, amount)
+		} else {
+			amounts[sendBlock.TokenId] = amount
+		}
+	}
+
+	tokenIds := []viteTypes.TokenTypeId{}
+	for _, currency := range currencies {
+		tokenId, err := CurrencyTokenId(currency)
+		if err != nil {
+			return nil, err
+		}
+		tokenIds = append(tokenIds, tokenId)
+	}
+	if len(currencies) == 0 {
+		for tokenId := range amounts {
+			tokenIds = append(tokenIds, tokenId)
+		}
+		sort.Slice(tokenIds, func(i, j int) bool {
+			return tokenIds[i].Hex() < tokenIds[j].Hex()
+		})
+	}
+
+	tokenCurrencies, err := ec.tokens.Currencies(ctx, tokenIds)
+	if err != nil {
+		return nil, err
+	}
+
+	balances := make([]*types.Amount, len(tokenIds))
+	for i, tokenId := range tokenIds {
+		value, ok := amounts[tokenId]
+		if !ok {
+			value = big.NewInt(0)
+		}
+		balances[i] = &types.Amount{
+			Value:    value.String(),
+			Currency: tokenCurrencies[i],
+		}
+	}
+
+	return &types.AccountBalanceResponse{
+		BlockIdentifier: ec.getBlockIdentifier(block),
+		Balances:        balances,
+	}, nil
+}
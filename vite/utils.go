@@ -2,12 +2,15 @@ package vite
 
 import (
 	"crypto/ed25519"
+	"encoding/hex"
 	"fmt"
 
 	"github.com/azbuky/rosetta-vite/utils"
 	"github.com/coinbase/rosetta-sdk-go/types"
+	viteTypes "github.com/vitelabs/go-vite/common/types"
 	"github.com/vitelabs/go-vite/ledger"
 	"github.com/vitelabs/go-vite/rpcapi/api"
+	"github.com/vitelabs/go-vite/vm/util"
 )
 
 func ConvertSecondsToMiliseconds(time int64) int64 {
@@ -103,6 +106,22 @@ func CurrencyForAccountBlock(account *api.AccountBlock) *types.Currency {
 	}
 }
 
+// CurrencyTokenId returns the token type id in the tti metadata of a
+// currency, an error wrapping ErrCurrencyInvalid is returned if it is
+// missing or invalid.
+func CurrencyTokenId(currency *types.Currency) (viteTypes.TokenTypeId, error) {
+	tti, ok := currency.Metadata["tti"].(string)
+	if !ok {
+		return viteTypes.TokenTypeId{}, fmt.Errorf("%w: %s has no tti metadata", ErrCurrencyInvalid, currency.Symbol)
+	}
+	tokenId, err := viteTypes.HexToTokenTypeId(tti)
+	if err != nil {
+		return viteTypes.TokenTypeId{}, fmt.Errorf("%w: %s is not a valid tti", ErrCurrencyInvalid, tti)
+	}
+
+	return tokenId, nil
+}
+
 func AmountForAccountBlock(account *api.AccountBlock, negateValue bool) *types.Amount {
 	value := "0"
 	if account.Amount != nil {
@@ -176,6 +195,27 @@ func RequestOperationForAccountBlock(accountBlock *api.AccountBlock, index int64
 	}, nil
 }
 
+// createContractOperationMetadata decodes the contract parameters
+// prefixing the data of a create contract block into the metadata
+// of a CREATE_CONTRACT operation. The contract address is set as
+// toAddress.
+func createContractOperationMetadata(accountBlock *api.AccountBlock) (map[string]interface{}, error) {
+	data := accountBlock.Data
+	metadata, err := utils.MarshalJSONMap(CreateContractOperationMetadata{
+		Bytecode:        hex.EncodeToString(data[createContractDataPrefixSize:]),
+		Gid:             util.GetGidFromCreateContractData(data).Hex(),
+		ConfirmTimes:    util.GetSnapshotCountFromCreateContractData(data),
+		SeedCount:       util.GetSnapshotWithSeedCountCountFromCreateContractData(data),
+		QuotaMultiplier: data[createContractDataPrefixSize-1],
+	})
+	if err != nil {
+		return nil, err
+	}
+	metadata[MetadataToAddressKey] = accountBlock.ToAddress.Hex()
+
+	return metadata, nil
+}
+
 func ResponseOperationForAccountBlock(accountBlock *api.AccountBlock, index int64, includeStatus bool) (*types.Operation, error) {
 	if !ledger.IsReceiveBlock(accountBlock.BlockType) {
 		return nil, fmt.Errorf("incorrect account block type")
@@ -328,6 +368,28 @@ func OperationsForAccountBlock(accountBlock *api.AccountBlock, includeStatus boo
 	}
 }
 
+// ParseOperationsForAccountBlock returns the operations of an account
+// block built by the Construction API. The data of a create contract
+// block is in the current create contract format, so the request
+// operation has the contract parameters as metadata instead of data.
+func ParseOperationsForAccountBlock(accountBlock *api.AccountBlock) ([]*types.Operation, error) {
+	ops, err := OperationsForAccountBlock(accountBlock, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if accountBlock.BlockType == ledger.BlockTypeSendCreate &&
+		len(accountBlock.Data) > createContractDataPrefixSize {
+		metadata, err := createContractOperationMetadata(accountBlock)
+		if err != nil {
+			return nil, err
+		}
+		ops[0].Metadata = metadata
+	}
+
+	return ops, nil
+}
+
 // Converts a vite account block to a rosetta transaction
 func AccountBlockToTransaction(accountBlock *api.AccountBlock, includeStatus bool) (*types.Transaction, error) {
 	ops, err := OperationsForAccountBlock(accountBlock, includeStatus)